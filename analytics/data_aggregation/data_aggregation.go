@@ -4,6 +4,7 @@ package data_aggregation
 
 import (
 	"context"
+	"errors"
 	"log"
 	"sync"
 	"time"
@@ -84,7 +85,22 @@ func (a Aggregator) GetMatchIDsForAccounts(ctx context.Context, r region.Region,
 		// Process each account concurrently.
 		go func() {
 			defer wg.Done()
-			matchlist, err := a.client.GetMatchlist(ctx, r, account, &opts)
+			// Walk the full match history rather than the first page.
+			var (
+				matchlist apiclient.Matchlist
+				err       error
+			)
+			it := apiclient.NewMatchlistIterator(a.client, r, account, &opts)
+			for {
+				m, nextErr := it.Next(ctx)
+				if nextErr != nil {
+					if !errors.Is(nextErr, apiclient.ErrIteratorDone) {
+						err = nextErr
+					}
+					break
+				}
+				matchlist.Matches = append(matchlist.Matches, *m)
+			}
 			if err == nil {
 				var (
 					subwg   sync.WaitGroup
@@ -150,12 +166,12 @@ func (a Aggregator) UploadMatches(ctx context.Context, r region.Region, matches
 	wg := sync.WaitGroup{}
 	for m := range matches {
 		match, err := a.client.GetMatch(ctx, r, m)
-		if err != nil && err != apiclient.ErrDataNotFound {
+		if err != nil && !errors.Is(err, apiclient.ErrDataNotFound) {
 			log.Printf("GetMatch failed for region %s game %d: %v", r, m, err)
 			continue
 		}
 		timeline, err := a.client.GetMatchTimeline(ctx, r, m)
-		if err != nil && err != apiclient.ErrDataNotFound {
+		if err != nil && !errors.Is(err, apiclient.ErrDataNotFound) {
 			log.Printf("GetMatchTimeline failed for region %s game %d: %v", r, m, err)
 			continue
 		}
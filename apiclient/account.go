@@ -0,0 +1,91 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/yuhanfang/riot/constants/game"
+	"github.com/yuhanfang/riot/constants/region"
+)
+
+// Account is a Riot account, which is shared across all Riot games.
+type Account struct {
+	PUUID    string `json:"puuid"`    // PUUID is the player universally unique identifier.
+	GameName string `json:"gameName"` // Game name portion of the Riot ID, before the "#".
+	TagLine  string `json:"tagLine"`  // Tag line portion of the Riot ID, after the "#".
+}
+
+// RiotID returns the account's Riot ID in "gameName#tagLine" form.
+func (a *Account) RiotID() string {
+	return a.GameName + "#" + a.TagLine
+}
+
+// ActiveShard is the shard on which a player is active for a given game.
+type ActiveShard struct {
+	PUUID       string    `json:"puuid"`
+	Game        game.Game `json:"game"`
+	ActiveShard string    `json:"activeShard"`
+}
+
+func (c *client) GetAccountByPUUID(ctx context.Context, r region.Routing, puuid string) (*Account, error) {
+	var res Account
+	_, err := c.dispatchAndUnmarshalRouting(ctx, r, "/riot/account/v1/accounts/by-puuid", fmt.Sprintf("/%s", puuid), nil, "", &res)
+	return &res, err
+}
+
+func (c *client) GetAccountByRiotID(ctx context.Context, r region.Routing, gameName, tagLine string) (*Account, error) {
+	var res Account
+	_, err := c.dispatchAndUnmarshalRouting(ctx, r, "/riot/account/v1/accounts/by-riot-id", fmt.Sprintf("/%s/%s", url.PathEscape(gameName), url.PathEscape(tagLine)), nil, "", &res)
+	return &res, err
+}
+
+func (c *client) GetActiveShard(ctx context.Context, r region.Routing, g game.Game, puuid string) (*ActiveShard, error) {
+	var res ActiveShard
+	_, err := c.dispatchAndUnmarshalRouting(ctx, r, "/riot/account/v1/active-shards/by-game", fmt.Sprintf("/%s/by-puuid/%s", g, puuid), nil, "", &res)
+	return &res, err
+}
+
+// ParseRiotID splits a Riot ID of the form "gameName#tagLine" into its game
+// name and tag line. Surrounding whitespace is ignored. Returns ErrBadRiotID
+// if either part is missing.
+func ParseRiotID(riotID string) (gameName, tagLine string, err error) {
+	i := strings.LastIndex(riotID, "#")
+	if i < 0 {
+		return "", "", ErrBadRiotID
+	}
+	gameName = strings.TrimSpace(riotID[:i])
+	tagLine = strings.TrimSpace(riotID[i+1:])
+	if gameName == "" || tagLine == "" {
+		return "", "", ErrBadRiotID
+	}
+	return gameName, tagLine, nil
+}
+
+// AccountRouting returns the regional cluster that serves account-v1 for the
+// given platform. Account data is global, so any cluster returns the same
+// result, but the cluster nearest to the platform is the fastest. SEA does not
+// serve account-v1, so its platforms are routed to Asia.
+func AccountRouting(r region.Region) region.Routing {
+	if rt := r.Routing(); rt != region.SEA {
+		return rt
+	}
+	return region.Asia
+}
+
+// GetSummonerByRiotID resolves a Riot ID of the form "gameName#tagLine" to the
+// summoner on the given platform. It looks up the account through account-v1
+// and then the summoner by PUUID, so it works with any Client, including
+// cached clients.
+func GetSummonerByRiotID(ctx context.Context, c Client, r region.Region, riotID string) (*Summoner, error) {
+	gameName, tagLine, err := ParseRiotID(riotID)
+	if err != nil {
+		return nil, err
+	}
+	account, err := c.GetAccountByRiotID(ctx, AccountRouting(r), gameName, tagLine)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetBySummonerPUUID(ctx, r, account.PUUID)
+}
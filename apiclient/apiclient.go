@@ -9,20 +9,65 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/yuhanfang/riot/constants/challenge"
 	"github.com/yuhanfang/riot/constants/champion"
+	"github.com/yuhanfang/riot/constants/division"
+	"github.com/yuhanfang/riot/constants/game"
 	"github.com/yuhanfang/riot/constants/queue"
 	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/constants/tier"
 	"github.com/yuhanfang/riot/external"
 	"github.com/yuhanfang/riot/ratelimit"
 )
 
 // Client accesses the Riot API. Use New() to retrieve a valid instance.
 type Client interface {
+	// ----- Account API -----
+
+	// GetAccountByPUUID returns the Riot account with the given PUUID.
+	GetAccountByPUUID(ctx context.Context, r region.Routing, puuid string) (*Account, error)
+
+	// GetAccountByRiotID returns the Riot account with the given Riot ID, which
+	// is the game name and tag line, e.g. "Name" and "NA1" for "Name#NA1". Use
+	// GetSummonerByRiotID to resolve a Riot ID directly to a summoner.
+	GetAccountByRiotID(ctx context.Context, r region.Routing, gameName, tagLine string) (*Account, error)
+
+	// GetActiveShard returns the shard on which the player is active for the
+	// given game.
+	GetActiveShard(ctx context.Context, r region.Routing, g game.Game, puuid string) (*ActiveShard, error)
+
+	// ----- Challenges API -----
+
+	// GetChallengeConfigs returns the configuration of every challenge.
+	GetChallengeConfigs(ctx context.Context, r region.Region) ([]ChallengeConfig, error)
+
+	// GetChallengePercentiles returns, for every challenge, the fraction of
+	// players that have reached each level.
+	GetChallengePercentiles(ctx context.Context, r region.Region) (map[int64]map[challenge.Level]float64, error)
+
+	// GetChallengeConfig returns the configuration of the given challenge.
+	GetChallengeConfig(ctx context.Context, r region.Region, challengeID int64) (*ChallengeConfig, error)
+
+	// GetChallengePercentilesByID returns the fraction of players that have
+	// reached each level of the given challenge.
+	GetChallengePercentilesByID(ctx context.Context, r region.Region, challengeID int64) (map[challenge.Level]float64, error)
+
+	// GetChallengeLeaderboard returns the top players of the given challenge at
+	// an apex level, i.e. one of challenge.Apex(). If limit is positive, at
+	// most limit players are returned.
+	GetChallengeLeaderboard(ctx context.Context, r region.Region, challengeID int64, level challenge.Level, limit int) ([]ChallengeApexPlayer, error)
+
+	// GetPlayerChallenges returns the progress of the given PUUID in every
+	// challenge.
+	GetPlayerChallenges(ctx context.Context, r region.Region, puuid string) (*PlayerChallenges, error)
+
 	// ----- Champion Mastery API -----
 
 	// GetAllChampionMasteries returns all champion mastery entries sorted by
@@ -36,14 +81,62 @@ type Client interface {
 	// which is the sum of individual champion mastery levels.
 	GetChampionMasteryScore(ctx context.Context, r region.Region, summonerID string) (int, error)
 
+	// GetAllChampionMasteriesByPUUID returns all champion mastery entries for
+	// the given PUUID sorted by number of champion points descending.
+	GetAllChampionMasteriesByPUUID(ctx context.Context, r region.Region, puuid string) ([]ChampionMastery, error)
+
+	// GetChampionMasteryByPUUID returns champion mastery by PUUID and champion.
+	GetChampionMasteryByPUUID(ctx context.Context, r region.Region, puuid string, champ champion.Champion) (*ChampionMastery, error)
+
+	// GetTopChampionMasteries returns the given number of champion mastery
+	// entries with the most champion points for the given PUUID. A count of
+	// zero uses the API default of 3.
+	GetTopChampionMasteries(ctx context.Context, r region.Region, puuid string, count int) ([]ChampionMastery, error)
+
+	// GetChampionMasteryScoreByPUUID returns the total champion mastery score
+	// for the given PUUID.
+	GetChampionMasteryScoreByPUUID(ctx context.Context, r region.Region, puuid string) (int, error)
+
 	// ----- Champions API -----
 
 	// GetChampions returns all champions.
+	//
+	// Deprecated: Riot has retired this endpoint. Use GetChampionRotations for
+	// free champions.
 	GetChampions(ctx context.Context, r region.Region) (*ChampionList, error)
 
 	// GetChampionByID returns champion information for a specific champion.
+	//
+	// Deprecated: Riot has retired this endpoint. Use GetChampionRotations for
+	// free champions.
 	GetChampionByID(ctx context.Context, r region.Region, champ champion.Champion) (*Champion, error)
 
+	// GetChampionRotations returns the current free champion rotation,
+	// including the separate rotation for new players.
+	GetChampionRotations(ctx context.Context, r region.Region) (*ChampionRotation, error)
+
+	// ----- Clash API -----
+
+	// GetClashPlayersBySummoner returns the Clash registrations of the given
+	// summoner ID, one for each active or upcoming tournament.
+	GetClashPlayersBySummoner(ctx context.Context, r region.Region, summonerID string) ([]ClashPlayer, error)
+
+	// GetClashPlayersByPUUID returns the Clash registrations of the given PUUID,
+	// one for each active or upcoming tournament.
+	GetClashPlayersByPUUID(ctx context.Context, r region.Region, puuid string) ([]ClashPlayer, error)
+
+	// GetClashTeam returns the Clash team with the given ID.
+	GetClashTeam(ctx context.Context, r region.Region, teamID string) (*ClashTeam, error)
+
+	// GetClashTournaments returns all active or upcoming Clash tournaments.
+	GetClashTournaments(ctx context.Context, r region.Region) ([]ClashTournament, error)
+
+	// GetClashTournamentByTeam returns the Clash tournament of the given team.
+	GetClashTournamentByTeam(ctx context.Context, r region.Region, teamID string) (*ClashTournament, error)
+
+	// GetClashTournament returns the Clash tournament with the given ID.
+	GetClashTournament(ctx context.Context, r region.Region, tournamentID int) (*ClashTournament, error)
+
 	// ----- League API -----
 
 	// GetChallengerLeague returns the challenger league for the given queue.
@@ -63,6 +156,17 @@ type Client interface {
 	// entries.
 	GetLeagueByID(ctx context.Context, r region.Region, leagueID string) (*LeagueList, error)
 
+	// GetLeagueEntries returns one page of league entries in the given queue,
+	// tier and division, for tiers below master. Pages start at 1. An empty
+	// page means that there are no more entries. Use NewLeagueEntriesIterator
+	// to walk all pages.
+	GetLeagueEntries(ctx context.Context, r region.Region, q queue.Queue, t tier.Tier, d division.Division, page int) ([]LeaguePosition, error)
+
+	// GetLeagueExpEntries is like GetLeagueEntries, but uses the experimental
+	// league-exp-v4 API, which also serves master and above. Those tiers have
+	// the single division I.
+	GetLeagueExpEntries(ctx context.Context, r region.Region, q queue.Queue, t tier.Tier, d division.Division, page int) ([]LeaguePosition, error)
+
 	// ----- Match API -----
 
 	// GetMatch returns a match by match ID.
@@ -77,6 +181,20 @@ type Client interface {
 	// GetRecentMatchlist returns the last 20 matches played on the given account ID.
 	GetRecentMatchlist(ctx context.Context, r region.Region, accountID string) (*Matchlist, error)
 
+	// ----- Match-v5 API -----
+
+	// GetMatchIDsByPUUID returns match IDs such as "NA1_123" for games played
+	// by the given PUUID, most recent first, filtered using given filter
+	// parameters, if any. The call is routed to the regional cluster that
+	// serves the given platform.
+	GetMatchIDsByPUUID(ctx context.Context, r region.Region, puuid string, opts *GetMatchIDsOptions) ([]string, error)
+
+	// GetMatchV5 returns a match by match-v5 match ID, for example "NA1_123".
+	GetMatchV5(ctx context.Context, r region.Region, matchID string) (*MatchV5, error)
+
+	// GetMatchTimelineV5 returns a match timeline by match-v5 match ID.
+	GetMatchTimelineV5(ctx context.Context, r region.Region, matchID string) (*MatchTimelineV5, error)
+
 	// ----- Spectator API -----
 
 	// GetFeaturedGames returns a list of featured games.
@@ -86,6 +204,21 @@ type Client interface {
 	// summoner ID.
 	GetCurrentGameInfoBySummoner(ctx context.Context, r region.Region, summonerID string) (*CurrentGameInfo, error)
 
+	// GetFeaturedGamesV5 returns a list of featured games from spectator-v5,
+	// which identifies participants by Riot ID and PUUID.
+	GetFeaturedGamesV5(ctx context.Context, r region.Region) (*FeaturedGames, error)
+
+	// GetCurrentGameInfoByPUUID returns current game information for a given
+	// PUUID from spectator-v5, which identifies participants by Riot ID and
+	// PUUID.
+	GetCurrentGameInfoByPUUID(ctx context.Context, r region.Region, puuid string) (*CurrentGameInfo, error)
+
+	// ----- Status API -----
+
+	// GetPlatformStatus returns the maintenances and incidents of the given
+	// platform. Use a StatusWatcher to be notified of changes.
+	GetPlatformStatus(ctx context.Context, r region.Region) (*PlatformStatus, error)
+
 	// ----- Summoner API -----
 
 	// GetByAccountID returns a summoner by account ID.
@@ -108,19 +241,38 @@ type Client interface {
 
 // client is the internal implementation of Client.
 type client struct {
-	key string
-	c   external.Doer
-	r   ratelimit.Limiter
+	key   string
+	c     external.Doer
+	r     ratelimit.Limiter
+	retry RetryPolicy
+
+	// baseURLs maps upper case platforms and routing values to base URLs that
+	// override their default hosts.
+	baseURLs       map[string]string
+	defaultBaseURL string
+
+	header        http.Header
+	requestHooks  []RequestHook
+	responseHooks []ResponseHook
 }
 
+// Option configures optional behavior of a Client returned by New.
+type Option func(*client)
+
 // New returns a Client configured for the given API client and underlying HTTP
-// client. The returned Client is threadsafe.
-func New(key string, httpClient external.Doer, limiter ratelimit.Limiter) Client {
-	return &client{
-		key: key,
-		c:   httpClient,
-		r:   limiter,
+// client, with any given options applied. The returned Client is threadsafe.
+func New(key string, httpClient external.Doer, limiter ratelimit.Limiter, opts ...Option) Client {
+	c := &client{
+		key:      key,
+		c:        httpClient,
+		r:        limiter,
+		baseURLs: make(map[string]string),
+		header:   make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
 // dispatchAndUnmarshalWithUniquifier is the same as dispatchAndUnmarshal,
@@ -128,67 +280,132 @@ func New(key string, httpClient external.Doer, limiter ratelimit.Limiter) Client
 // handling of certain methods that have different quota buckets depending on
 // the relative path.
 func (c *client) dispatchAndUnmarshalWithUniquifier(ctx context.Context, r region.Region, m string, relativePath string, v url.Values, u string, dest interface{}) (*http.Response, error) {
-	res, err := c.dispatchMethod(ctx, r, m, relativePath, v, u)
-	if err != nil {
-		return res, err
+	return c.dispatchAndUnmarshalToHost(ctx, c.baseURL(string(r), r.Host), string(r), m, relativePath, v, u, dest)
+}
+
+// dispatchAndUnmarshalRouting is the same as
+// dispatchAndUnmarshalWithUniquifier, except that the method is served by the
+// given regional cluster instead of by a platform. Quota is tracked under the
+// routing value, since regional clusters have their own quota buckets.
+func (c *client) dispatchAndUnmarshalRouting(ctx context.Context, r region.Routing, m string, relativePath string, v url.Values, u string, dest interface{}) (*http.Response, error) {
+	return c.dispatchAndUnmarshalToHost(ctx, c.baseURL(string(r), r.Host), string(r), m, relativePath, v, u, dest)
+}
+
+// dispatchAndUnmarshalToHost dispatches the method to the given host (see
+// dispatchMethod), and unmarshals the response as documented in
+// dispatchAndUnmarshal. Failed attempts are retried according to the client's
+// retry policy.
+func (c *client) dispatchAndUnmarshalToHost(ctx context.Context, host, quotaRegion string, m string, relativePath string, v url.Values, u string, dest interface{}) (*http.Response, error) {
+	return c.sendAndUnmarshal(ctx, http.MethodGet, host, quotaRegion, m, relativePath, v, u, nil, dest)
+}
+
+// sendAndUnmarshal is the same as dispatchAndUnmarshalToHost, except that the
+// request is made with the given HTTP method. If body is not nil, it is
+// marshaled and sent as the JSON request body. If dest is nil, the response
+// body is not unmarshaled, which supports methods that return no content.
+// Calls that are not GETs are only retried if they are rate limited.
+func (c *client) sendAndUnmarshal(ctx context.Context, httpMethod, host, quotaRegion string, m string, relativePath string, v url.Values, u string, body interface{}, dest interface{}) (*http.Response, error) {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
 	}
-	if res.StatusCode != http.StatusOK {
-		err, ok := httpErrors[res.StatusCode]
-		if !ok {
-			err = ErrBadHTTPStatus
+	for attempt := 1; ; attempt++ {
+		res, b, err := c.dispatchAndRead(ctx, httpMethod, host, quotaRegion, m, relativePath, v, u, payload)
+		delay, retry := c.retry.retryDelay(attempt, err)
+		if !retry || !idempotent(httpMethod, err) {
+			if err != nil || dest == nil {
+				return res, err
+			}
+			return res, json.Unmarshal(b, dest)
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return res, err
 		}
-		return res, err
+	}
+}
+
+// dispatchAndRead makes a single attempt at dispatching the method, and reads
+// the response body. The response body is reset to read from the beginning of
+// the stream. If the response status is not HTTP OK, an *APIError is
+// returned.
+func (c *client) dispatchAndRead(ctx context.Context, httpMethod, host, quotaRegion string, m string, relativePath string, v url.Values, u string, payload []byte) (*http.Response, []byte, error) {
+	res, err := c.dispatchMethod(ctx, httpMethod, host, quotaRegion, m, relativePath, v, u, payload)
+	if err != nil {
+		return res, nil, err
 	}
 
 	b, err := ioutil.ReadAll(res.Body)
 	res.Body.Close()
 	res.Body = ioutil.NopCloser(bytes.NewReader(b))
 
-	// The body is in good state, so now we can return if there was an IO problem.
-	if err != nil {
-		return res, err
+	if res.StatusCode != http.StatusOK {
+		return res, b, newAPIError(res, m, strings.ToUpper(quotaRegion), b)
 	}
-	err = json.Unmarshal(b, dest)
 
-	return res, err
+	// The body is in good state, so now we can return if there was an IO problem.
+	return res, b, err
 }
 
 // dispatchAndUnmarshal dispatches the method (see dispatchMethod). If the
 // method returns HTTP okay, then read the body into a buffer and attempt to
-// unmarshal it into the supplied destination. Otherwise, the method returns
-// one of the documented errors. In any case, the body is set to read from the
-// beginning of the stream and is left open, as if the response were returned
-// directly from an HTTP request.
+// unmarshal it into the supplied destination. Otherwise, the method returns an
+// *APIError wrapping one of the documented errors. In any case, the body is
+// set to read from the beginning of the stream and is left open, as if the
+// response were returned directly from an HTTP request.
 func (c *client) dispatchAndUnmarshal(ctx context.Context, r region.Region, m string, relativePath string, v url.Values, dest interface{}) (*http.Response, error) {
 	return c.dispatchAndUnmarshalWithUniquifier(ctx, r, m, relativePath, v, "", dest)
 }
 
-// dispatchMethod calls the given API method for the given region. The
-// relativePath is appended to the method to form the REST endpoint. The given
-// URL values are encoded and passed as URL parameters following the REST
-// endpoint.
-func (c *client) dispatchMethod(ctx context.Context, r region.Region, m string, relativePath string, v url.Values, uniquifier string) (*http.Response, error) {
+// dispatchMethod calls the given API method on the given host using the given
+// HTTP method. The relativePath, if any, is appended to the method to form the
+// REST endpoint. The given URL values are encoded and passed as URL parameters
+// following the REST endpoint. A non-nil payload is sent as the JSON request
+// body. Quota is acquired for the given quota region, which is the platform or
+// regional cluster that serves the host. Configured headers and hooks are
+// applied to the request and response.
+func (c *client) dispatchMethod(ctx context.Context, httpMethod, host, quotaRegion string, m string, relativePath string, v url.Values, uniquifier string, payload []byte) (*http.Response, error) {
 	var suffix, separator string
 
 	if len(v) > 0 {
 		suffix = fmt.Sprintf("?%s", v.Encode())
 	}
-	if !strings.HasPrefix(relativePath, "/") {
+	if relativePath != "" && !strings.HasPrefix(relativePath, "/") {
 		separator = "/"
 	}
-	path := r.Host() + m + separator + relativePath + suffix
-	req, err := http.NewRequest("GET", path, nil)
+	path := host + m + separator + relativePath + suffix
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(httpMethod, path, body)
 	if err != nil {
 		return nil, err
 	}
 	req = req.WithContext(ctx)
-	req.Header.Add("X-Riot-Token", c.key)
+	for k, vals := range c.header {
+		req.Header[k] = append([]string(nil), vals...)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Riot-Token", c.key)
+	for _, hook := range c.requestHooks {
+		req, err = hook(req)
+		if err != nil {
+			return nil, err
+		}
+	}
 
+	acquireStart := time.Now()
 	done, _, err := c.r.Acquire(ctx, ratelimit.Invocation{
 		ApplicationKey: c.key,
-		Region:         strings.ToUpper(string(r)),
+		Region:         strings.ToUpper(quotaRegion),
 		Method:         strings.ToLower(m),
 		Uniquifier:     uniquifier,
+		Priority:       ratelimit.PriorityFromContext(ctx),
 	})
 
 	if err != nil {
@@ -196,10 +413,20 @@ func (c *client) dispatchMethod(ctx context.Context, r region.Region, m string,
 	}
 
 	// If either the done() or the HTTP request is an error, then return error.
+	requestStart := time.Now()
 	res, err := c.c.Do(req)
+	collectMetadata(ctx, quotaRegion, m, uniquifier, requestStart.Sub(acquireStart), time.Since(requestStart), res, err)
 	derr := done(res)
 	if err == nil {
 		err = derr
 	}
-	return res, err
+	if err != nil {
+		return res, err
+	}
+	for _, hook := range c.responseHooks {
+		if err := hook(req, res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
 }
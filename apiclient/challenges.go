@@ -0,0 +1,151 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/yuhanfang/riot/constants/challenge"
+	"github.com/yuhanfang/riot/constants/language"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/types"
+)
+
+// ChallengeConfig describes a challenge.
+type ChallengeConfig struct {
+	ID             int64                                       `json:"id"`
+	LocalizedNames map[language.Language]ChallengeLocalization `json:"localizedNames"`
+	State          string                                      `json:"state"`    // One of DISABLED, HIDDEN, ENABLED or ARCHIVED.
+	Tracking       string                                      `json:"tracking"` // One of LIFETIME or SEASON.
+	StartTimestamp types.Milliseconds                          `json:"startTimestamp"`
+	EndTimestamp   types.Milliseconds                          `json:"endTimestamp"`
+	Leaderboard    bool                                        `json:"leaderboard"` // True if the apex levels have leaderboards.
+
+	// Thresholds maps levels to the value required to reach them.
+	Thresholds map[challenge.Level]float64 `json:"thresholds"`
+}
+
+// Localization returns the localized names in the given language, or in
+// English if there are none in the language.
+func (c *ChallengeConfig) Localization(lang language.Language) ChallengeLocalization {
+	if l, ok := c.LocalizedNames[lang]; ok {
+		return l
+	}
+	return c.LocalizedNames[language.EnglishUnitedStates]
+}
+
+// ChallengeLocalization is the localized text of a challenge.
+type ChallengeLocalization struct {
+	Name             string `json:"name"`
+	Description      string `json:"description"`
+	ShortDescription string `json:"shortDescription"`
+}
+
+// ChallengeApexPlayer is a player on a challenge leaderboard.
+type ChallengeApexPlayer struct {
+	PUUID    string  `json:"puuid"`
+	Value    float64 `json:"value"`
+	Position int     `json:"position"`
+}
+
+// PlayerChallenges is a player's progress in all challenges.
+type PlayerChallenges struct {
+	Challenges     []ChallengeProgress        `json:"challenges"`
+	Preferences    ChallengePreferences       `json:"preferences"`
+	TotalPoints    ChallengePoints            `json:"totalPoints"`
+	CategoryPoints map[string]ChallengePoints `json:"categoryPoints"` // Keyed by category, e.g. "TEAMWORK".
+}
+
+// Rarest returns up to n challenges in which the player has reached a level,
+// sorted by percentile ascending, so that the challenges reached by the
+// fewest players come first. A negative n returns no challenges.
+func (p *PlayerChallenges) Rarest(n int) []ChallengeProgress {
+	var res []ChallengeProgress
+	for _, c := range p.Challenges {
+		if c.Level.Rank() > 0 {
+			res = append(res, c)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Percentile < res[j].Percentile
+	})
+	if n < 0 {
+		n = 0
+	}
+	if len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
+
+// ChallengeProgress is a player's progress in a challenge.
+type ChallengeProgress struct {
+	ChallengeID    int64              `json:"challengeId"`
+	Level          challenge.Level    `json:"level"`
+	Value          float64            `json:"value"`
+	Percentile     float64            `json:"percentile"` // Fraction of players that have reached the level, between 0 and 1.
+	AchievedTime   types.Milliseconds `json:"achievedTime"`
+	Position       int                `json:"position"`       // Leaderboard position, for apex levels.
+	PlayersInLevel int                `json:"playersInLevel"` // Leaderboard size, for apex levels.
+}
+
+// ChallengePreferences are the challenges and title that the player shows on
+// their profile.
+type ChallengePreferences struct {
+	BannerAccent             string  `json:"bannerAccent"`
+	Title                    string  `json:"title"`
+	ChallengeIDs             []int64 `json:"challengeIds"`
+	CrestBorder              string  `json:"crestBorder"`
+	PrestigeCrestBorderLevel int     `json:"prestigeCrestBorderLevel"`
+}
+
+// ChallengePoints are the points earned in all challenges, or in a category.
+type ChallengePoints struct {
+	Level      challenge.Level `json:"level"`
+	Current    int             `json:"current"`
+	Max        int             `json:"max"`
+	Percentile float64         `json:"percentile"`
+}
+
+func (c *client) GetChallengeConfigs(ctx context.Context, r region.Region) ([]ChallengeConfig, error) {
+	var res []ChallengeConfig
+	_, err := c.dispatchAndUnmarshalWithUniquifier(ctx, r, "/lol/challenges/v1/challenges", "/config", nil, "config", &res)
+	return res, err
+}
+
+func (c *client) GetChallengePercentiles(ctx context.Context, r region.Region) (map[int64]map[challenge.Level]float64, error) {
+	var res map[int64]map[challenge.Level]float64
+	_, err := c.dispatchAndUnmarshalWithUniquifier(ctx, r, "/lol/challenges/v1/challenges", "/percentiles", nil, "percentiles", &res)
+	return res, err
+}
+
+func (c *client) GetChallengeConfig(ctx context.Context, r region.Region, challengeID int64) (*ChallengeConfig, error) {
+	var res ChallengeConfig
+	_, err := c.dispatchAndUnmarshalWithUniquifier(ctx, r, "/lol/challenges/v1/challenges", fmt.Sprintf("/%d/config", challengeID), nil, "by-id-config", &res)
+	return &res, err
+}
+
+func (c *client) GetChallengePercentilesByID(ctx context.Context, r region.Region, challengeID int64) (map[challenge.Level]float64, error) {
+	var res map[challenge.Level]float64
+	_, err := c.dispatchAndUnmarshalWithUniquifier(ctx, r, "/lol/challenges/v1/challenges", fmt.Sprintf("/%d/percentiles", challengeID), nil, "by-id-percentiles", &res)
+	return res, err
+}
+
+func (c *client) GetChallengeLeaderboard(ctx context.Context, r region.Region, challengeID int64, level challenge.Level, limit int) ([]ChallengeApexPlayer, error) {
+	var (
+		res  []ChallengeApexPlayer
+		vals url.Values
+	)
+	if limit > 0 {
+		vals = url.Values{"limit": []string{fmt.Sprintf("%d", limit)}}
+	}
+	_, err := c.dispatchAndUnmarshalWithUniquifier(ctx, r, "/lol/challenges/v1/challenges", fmt.Sprintf("/%d/leaderboards/by-level/%s", challengeID, level), vals, "leaderboards", &res)
+	return res, err
+}
+
+func (c *client) GetPlayerChallenges(ctx context.Context, r region.Region, puuid string) (*PlayerChallenges, error) {
+	var res PlayerChallenges
+	_, err := c.dispatchAndUnmarshalWithUniquifier(ctx, r, "/lol/challenges/v1/player-data", fmt.Sprintf("/%s", puuid), nil, "by-puuid", &res)
+	return &res, err
+}
@@ -0,0 +1,104 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/yuhanfang/riot/constants/challenge"
+	"github.com/yuhanfang/riot/constants/language"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/testing/doertest"
+)
+
+const challengePercentiles = `{
+  "101000": {"IRON": 0.92, "GOLD": 0.31, "MASTER": 0.004},
+  "2022000": {"NONE": 1, "DIAMOND": 0.05}
+}`
+
+func TestGetChallengePercentiles(t *testing.T) {
+	var path string
+	doer := doertest.StaticResponse(http.StatusOK, nil, challengePercentiles)
+	c := New("key", doertest.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		path = req.URL.Path
+		return doer(req)
+	}), ratelimit.NewLimiter())
+
+	got, err := c.GetChallengePercentiles(context.Background(), region.NA1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/lol/challenges/v1/challenges/percentiles" {
+		t.Errorf("got path %s", path)
+	}
+	want := map[int64]map[challenge.Level]float64{
+		101000:  {challenge.Iron: 0.92, challenge.Gold: 0.31, challenge.Master: 0.004},
+		2022000: {challenge.None: 1, challenge.Diamond: 0.05},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got percentiles %v, want %v", got, want)
+	}
+}
+
+func TestPlayerChallengesRarest(t *testing.T) {
+	p := PlayerChallenges{
+		Challenges: []ChallengeProgress{
+			{ChallengeID: 1, Level: challenge.Gold, Percentile: 0.31},
+			{ChallengeID: 2, Level: challenge.None, Percentile: 0.001},
+			{ChallengeID: 3, Level: challenge.Master, Percentile: 0.004},
+			{ChallengeID: 4, Level: challenge.Iron, Percentile: 0.92},
+		},
+	}
+	var got []int64
+	for _, c := range p.Rarest(2) {
+		got = append(got, c.ChallengeID)
+	}
+	if want := []int64{3, 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got challenges %v, want %v", got, want)
+	}
+	if got := p.Rarest(-1); len(got) != 0 {
+		t.Errorf("got %d challenges for negative n, want 0", len(got))
+	}
+}
+
+func TestChallengeBuckets(t *testing.T) {
+	type bucket struct{ method, uniquifier string }
+	buckets := make(map[bucket]bool)
+	ctx := WithMetadataCollector(context.Background(), func(m *ResponseMetadata) {
+		buckets[bucket{m.Method, m.Uniquifier}] = true
+	})
+	c := New("key", doertest.StaticResponse(http.StatusOK, nil, "{}"), ratelimit.NewLimiter())
+
+	// Only the buckets matter, so errors decoding "{}" are ignored.
+	c.GetChallengeConfigs(ctx, region.NA1)
+	c.GetChallengePercentiles(ctx, region.NA1)
+	c.GetChallengeConfig(ctx, region.NA1, 1)
+	c.GetChallengePercentilesByID(ctx, region.NA1, 1)
+	c.GetChallengeLeaderboard(ctx, region.NA1, 1, challenge.Master, 0)
+	if _, err := c.GetPlayerChallenges(ctx, region.NA1, "puuid"); err != nil {
+		t.Fatal(err)
+	}
+	if len(buckets) != 6 {
+		t.Errorf("got %d rate limit buckets, want 6: %v", len(buckets), buckets)
+	}
+	if !buckets[bucket{"/lol/challenges/v1/player-data", "by-puuid"}] {
+		t.Errorf("player data has no by-puuid bucket: %v", buckets)
+	}
+}
+
+func TestChallengeConfigLocalization(t *testing.T) {
+	c := ChallengeConfig{
+		LocalizedNames: map[language.Language]ChallengeLocalization{
+			language.EnglishUnitedStates: {Name: "Wukong Clone"},
+			"fr_FR":                      {Name: "Clone de Wukong"},
+		},
+	}
+	if got := c.Localization("fr_FR").Name; got != "Clone de Wukong" {
+		t.Errorf("got French name %q", got)
+	}
+	if got := c.Localization("ko_KR").Name; got != "Wukong Clone" {
+		t.Errorf("got fallback name %q", got)
+	}
+}
@@ -3,6 +3,7 @@ package apiclient
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	"github.com/yuhanfang/riot/constants/champion"
 	"github.com/yuhanfang/riot/constants/region"
@@ -17,6 +18,32 @@ type ChampionMastery struct {
 	ChampionPointsUntilNextLevel int64             `json:"championPointsUntilNextLevel",datastore:",noindex"` // Number of points needed to achieve next level. Zero if player reached maximum champion level for this champion.
 	ChampionPointsSinceLastLevel int64             `json:"championPointsSinceLastLevel",datastore:",noindex"` // Number of points earned since current level has been achieved. Zero if player reached maximum champion level for this champion.
 	LastPlayTime                 int64             `json:"lastPlayTime",datastore:",noindex"`                 // Last time this champion was played by this player - in Unix milliseconds time format.
+
+	// The following fields are only returned by the PUUID methods.
+
+	PUUID                    string              `json:"puuid"`
+	TokensEarned             int                 `json:"tokensEarned"`             // Number of mastery tokens earned towards the next level.
+	MarkRequiredForNextLevel int                 `json:"markRequiredForNextLevel"` // Number of marks of mastery required to reach the next level.
+	ChampionSeasonMilestone  int                 `json:"championSeasonMilestone"`
+	MilestoneGrades          []string            `json:"milestoneGrades"` // Grades earned towards the next season milestone, e.g. "S-".
+	NextSeasonMilestone      NextSeasonMilestone `json:"nextSeasonMilestone"`
+}
+
+// NextSeasonMilestone describes the requirements and rewards of the next
+// champion season milestone.
+type NextSeasonMilestone struct {
+	// RequireGradeCounts maps grades, e.g. "A-", to the number of games with
+	// that grade or better that are required.
+	RequireGradeCounts map[string]int        `json:"requireGradeCounts"`
+	RewardMarks        int                   `json:"rewardMarks"`
+	Bonus              bool                  `json:"bonus"`
+	RewardConfig       MilestoneRewardConfig `json:"rewardConfig"`
+}
+
+type MilestoneRewardConfig struct {
+	RewardValue   string `json:"rewardValue"`
+	RewardType    string `json:"rewardType"`
+	MaximumReward int    `json:"maximumReward"`
 }
 
 func (c *client) GetAllChampionMasteries(ctx context.Context, r region.Region, summonerID string) ([]ChampionMastery, error) {
@@ -36,3 +63,33 @@ func (c *client) GetChampionMasteryScore(ctx context.Context, r region.Region, s
 	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/champion-mastery/v4/scores/by-summoner", fmt.Sprintf("/%s", summonerID), nil, &res)
 	return res, err
 }
+
+func (c *client) GetAllChampionMasteriesByPUUID(ctx context.Context, r region.Region, puuid string) ([]ChampionMastery, error) {
+	var res []ChampionMastery
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/champion-mastery/v4/champion-masteries/by-puuid", fmt.Sprintf("/%s", puuid), nil, &res)
+	return res, err
+}
+
+func (c *client) GetChampionMasteryByPUUID(ctx context.Context, r region.Region, puuid string, champ champion.Champion) (*ChampionMastery, error) {
+	var res ChampionMastery
+	_, err := c.dispatchAndUnmarshalWithUniquifier(ctx, r, "/lol/champion-mastery/v4/champion-masteries/by-puuid", fmt.Sprintf("/%s/by-champion/%d", puuid, champ), nil, "by-champion", &res)
+	return &res, err
+}
+
+func (c *client) GetTopChampionMasteries(ctx context.Context, r region.Region, puuid string, count int) ([]ChampionMastery, error) {
+	var (
+		res  []ChampionMastery
+		vals url.Values
+	)
+	if count > 0 {
+		vals = url.Values{"count": []string{fmt.Sprintf("%d", count)}}
+	}
+	_, err := c.dispatchAndUnmarshalWithUniquifier(ctx, r, "/lol/champion-mastery/v4/champion-masteries/by-puuid", fmt.Sprintf("/%s/top", puuid), vals, "top", &res)
+	return res, err
+}
+
+func (c *client) GetChampionMasteryScoreByPUUID(ctx context.Context, r region.Region, puuid string) (int, error) {
+	var res int
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/champion-mastery/v4/scores/by-puuid", fmt.Sprintf("/%s", puuid), nil, &res)
+	return res, err
+}
@@ -0,0 +1,62 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/yuhanfang/riot/constants/champion"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/testing/doertest"
+)
+
+func TestChampionMasteryByPUUID(t *testing.T) {
+	var urls []string
+	doer := doertest.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		urls = append(urls, req.URL.String())
+		body := `[{"championId": 22, "puuid": "puuid", "tokensEarned": 1, "markRequiredForNextLevel": 2}]`
+		if strings.Contains(req.URL.Path, "/by-champion/") {
+			body = `{"championId": 22, "puuid": "puuid"}`
+		} else if strings.Contains(req.URL.Path, "/scores/") {
+			body = `42`
+		}
+		return doertest.Response(req, http.StatusOK, nil, body), nil
+	})
+
+	type bucket struct{ method, uniquifier string }
+	buckets := make(map[bucket]bool)
+	ctx := WithMetadataCollector(context.Background(), func(m *ResponseMetadata) {
+		buckets[bucket{m.Method, m.Uniquifier}] = true
+	})
+	c := New("key", doer, ratelimit.NewLimiter())
+
+	all, err := c.GetAllChampionMasteriesByPUUID(ctx, region.NA1, "puuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if all[0].ChampionID != champion.Ashe || all[0].TokensEarned != 1 || all[0].MarkRequiredForNextLevel != 2 {
+		t.Errorf("got %+v, want Ashe with tokens and marks", all[0])
+	}
+	if _, err := c.GetChampionMasteryByPUUID(ctx, region.NA1, "puuid", champion.Ashe); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetTopChampionMasteries(ctx, region.NA1, "puuid", 5); err != nil {
+		t.Fatal(err)
+	}
+	score, err := c.GetChampionMasteryScoreByPUUID(ctx, region.NA1, "puuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if score != 42 {
+		t.Errorf("got score %d, want 42", score)
+	}
+
+	if len(buckets) != 4 {
+		t.Errorf("got %d rate limit buckets, want 4: %v", len(buckets), buckets)
+	}
+	if want := "https://na1.api.riotgames.com/lol/champion-mastery/v4/champion-masteries/by-puuid/puuid/top?count=5"; urls[2] != want {
+		t.Errorf("got URL %s, want %s", urls[2], want)
+	}
+}
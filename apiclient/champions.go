@@ -9,7 +9,17 @@ import (
 )
 
 type ChampionList struct {
-	champions []Champion `json:"champions",datastore:",noindex"` // The collection of champion information.
+	Champions []Champion `json:"champions",datastore:",noindex"` // The collection of champion information.
+}
+
+// ChampionRotation is the weekly free champion rotation.
+type ChampionRotation struct {
+	FreeChampionIDs []champion.Champion `json:"freeChampionIds"`
+
+	// FreeChampionIDsForNewPlayers are free to players up to and including
+	// MaxNewPlayerLevel.
+	FreeChampionIDsForNewPlayers []champion.Champion `json:"freeChampionIdsForNewPlayers"`
+	MaxNewPlayerLevel            int                 `json:"maxNewPlayerLevel"`
 }
 
 type Champion struct {
@@ -32,3 +42,9 @@ func (c *client) GetChampionByID(ctx context.Context, r region.Region, champ cha
 	_, err := c.dispatchAndUnmarshalWithUniquifier(ctx, r, "/lol/platform/v3/champions", fmt.Sprintf("/%d", champ), nil, "by-id", &res)
 	return &res, err
 }
+
+func (c *client) GetChampionRotations(ctx context.Context, r region.Region) (*ChampionRotation, error) {
+	var res ChampionRotation
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/platform/v3/champion-rotations", "", nil, &res)
+	return &res, err
+}
@@ -0,0 +1,35 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/yuhanfang/riot/constants/champion"
+)
+
+func TestChampionListUnmarshal(t *testing.T) {
+	var list ChampionList
+	if err := json.Unmarshal([]byte(`{"champions": [{"id": 22, "freeToPlay": true}]}`), &list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Champions) != 1 || list.Champions[0].ID != 22 || !list.Champions[0].FreeToPlay {
+		t.Errorf("got %+v, want Ashe free to play", list)
+	}
+}
+
+func TestChampionRotationUnmarshal(t *testing.T) {
+	var rotation ChampionRotation
+	b := []byte(`{"freeChampionIds": [22, 1], "freeChampionIdsForNewPlayers": [18], "maxNewPlayerLevel": 10}`)
+	if err := json.Unmarshal(b, &rotation); err != nil {
+		t.Fatal(err)
+	}
+	want := ChampionRotation{
+		FreeChampionIDs:              []champion.Champion{champion.Ashe, champion.Annie},
+		FreeChampionIDsForNewPlayers: []champion.Champion{champion.Tristana},
+		MaxNewPlayerLevel:            10,
+	}
+	if !reflect.DeepEqual(rotation, want) {
+		t.Errorf("got %+v, want %+v", rotation, want)
+	}
+}
@@ -0,0 +1,99 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/types"
+)
+
+// ClashPlayer is a player's registration for Clash.
+type ClashPlayer struct {
+	SummonerID string `json:"summonerId"` // Encrypted summoner ID.
+	PUUID      string `json:"puuid"`      // Only returned when looking up players by PUUID.
+	TeamID     string `json:"teamId"`     // Empty if the player has not joined a team.
+	Position   string `json:"position"`   // One of UNSELECTED, FILL, TOP, JUNGLE, MIDDLE, BOTTOM or UTILITY.
+	Role       string `json:"role"`       // One of CAPTAIN or MEMBER.
+}
+
+type ClashTeam struct {
+	ID           string        `json:"id"`
+	TournamentID int           `json:"tournamentId"`
+	Name         string        `json:"name"`
+	IconID       int           `json:"iconId"`
+	Tier         int           `json:"tier"`
+	Captain      string        `json:"captain"` // Encrypted summoner ID of the team captain.
+	Abbreviation string        `json:"abbreviation"`
+	Players      []ClashPlayer `json:"players"` // Team members.
+}
+
+type ClashTournament struct {
+	ID               int                    `json:"id"`
+	ThemeID          int                    `json:"themeId"`
+	NameKey          string                 `json:"nameKey"`          // Localization key for the tournament name, e.g. "bilgewater".
+	NameKeySecondary string                 `json:"nameKeySecondary"` // Localization key for the day of the tournament, e.g. "day_1".
+	Schedule         []ClashTournamentPhase `json:"schedule"`         // Tournament phases, one for each day of the tournament.
+}
+
+// NextPhase returns the first phase that is not cancelled and has not started
+// by the given time, or nil if there is none.
+func (t *ClashTournament) NextPhase(now time.Time) *ClashTournamentPhase {
+	var next *ClashTournamentPhase
+	for i := range t.Schedule {
+		p := &t.Schedule[i]
+		if p.Cancelled || !p.StartTime.Time().After(now) {
+			continue
+		}
+		if next == nil || p.StartTime < next.StartTime {
+			next = p
+		}
+	}
+	return next
+}
+
+type ClashTournamentPhase struct {
+	ID               int                `json:"id"`
+	RegistrationTime types.Milliseconds `json:"registrationTime"` // Time that registration opens.
+	StartTime        types.Milliseconds `json:"startTime"`
+	Cancelled        bool               `json:"cancelled"`
+}
+
+func (c *client) GetClashPlayersBySummoner(ctx context.Context, r region.Region, summonerID string) ([]ClashPlayer, error) {
+	var res []ClashPlayer
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/clash/v1/players/by-summoner", fmt.Sprintf("/%s", summonerID), nil, &res)
+	return res, err
+}
+
+func (c *client) GetClashPlayersByPUUID(ctx context.Context, r region.Region, puuid string) ([]ClashPlayer, error) {
+	var res []ClashPlayer
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/clash/v1/players/by-puuid", fmt.Sprintf("/%s", puuid), nil, &res)
+	return res, err
+}
+
+func (c *client) GetClashTeam(ctx context.Context, r region.Region, teamID string) (*ClashTeam, error) {
+	var res ClashTeam
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/clash/v1/teams", fmt.Sprintf("/%s", teamID), nil, &res)
+	return &res, err
+}
+
+func (c *client) GetClashTournaments(ctx context.Context, r region.Region) ([]ClashTournament, error) {
+	var res []ClashTournament
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/clash/v1/tournaments", "", nil, &res)
+	return res, err
+}
+
+func (c *client) GetClashTournamentByTeam(ctx context.Context, r region.Region, teamID string) (*ClashTournament, error) {
+	var res ClashTournament
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/clash/v1/tournaments/by-team", fmt.Sprintf("/%s", teamID), nil, &res)
+	return &res, err
+}
+
+func (c *client) GetClashTournament(ctx context.Context, r region.Region, tournamentID int) (*ClashTournament, error) {
+	var res ClashTournament
+	// Tournaments by ID are a separate API call from the tournament list, even
+	// though both have the same Method.
+	_, err := c.dispatchAndUnmarshalWithUniquifier(ctx, r, "/lol/clash/v1/tournaments", fmt.Sprintf("/%d", tournamentID), nil, "by-id", &res)
+	return &res, err
+}
@@ -0,0 +1,71 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/testing/doertest"
+	"github.com/yuhanfang/riot/types"
+)
+
+const clashTournaments = `[{
+  "id": 2001,
+  "themeId": 14,
+  "nameKey": "bilgewater",
+  "nameKeySecondary": "day_2",
+  "schedule": [
+    {"id": 3001, "registrationTime": 1700000000000, "startTime": 1700010000000, "cancelled": false}
+  ]
+}]`
+
+func TestGetClashTournaments(t *testing.T) {
+	var path string
+	doer := doertest.StaticResponse(http.StatusOK, nil, clashTournaments)
+	c := New("key", doertest.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		path = req.URL.Path
+		return doer(req)
+	}), ratelimit.NewLimiter())
+
+	tournaments, err := c.GetClashTournaments(context.Background(), region.NA1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/lol/clash/v1/tournaments" {
+		t.Errorf("got path %s", path)
+	}
+	if len(tournaments) != 1 {
+		t.Fatalf("got %d tournaments, want 1", len(tournaments))
+	}
+	got := tournaments[0]
+	if got.ID != 2001 || got.NameKey != "bilgewater" || len(got.Schedule) != 1 {
+		t.Fatalf("got tournament %+v", got)
+	}
+	if start := got.Schedule[0].StartTime.Time(); !start.Equal(time.Unix(1700010000, 0)) {
+		t.Errorf("got start time %v", start)
+	}
+}
+
+func TestClashTournamentNextPhase(t *testing.T) {
+	now := time.Unix(1000, 0)
+	ms := func(sec int64) types.Milliseconds {
+		return types.Milliseconds(sec * 1000)
+	}
+	tournament := ClashTournament{
+		Schedule: []ClashTournamentPhase{
+			{ID: 1, StartTime: ms(900)},
+			{ID: 2, StartTime: ms(1300)},
+			{ID: 3, StartTime: ms(1100), Cancelled: true},
+			{ID: 4, StartTime: ms(1200)},
+		},
+	}
+	if next := tournament.NextPhase(now); next == nil || next.ID != 4 {
+		t.Errorf("got next phase %+v, want ID 4", next)
+	}
+	if next := tournament.NextPhase(time.Unix(1300, 0)); next != nil {
+		t.Errorf("got next phase %+v, want nil", next)
+	}
+}
@@ -0,0 +1,44 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/external"
+	"github.com/yuhanfang/riot/ratelimit"
+)
+
+// Dispatcher makes raw calls to the Riot API with the same base URLs,
+// headers, hooks, rate limiting, retries and errors as a Client. It allows
+// packages that cover other Riot games, such as tft, to reuse the Client
+// plumbing. It is illegal to construct an instance directly. Use
+// NewDispatcher to return a valid instance. The Dispatcher is threadsafe.
+type Dispatcher struct {
+	c *client
+}
+
+// NewDispatcher returns a Dispatcher. The arguments and options are the same
+// as for New.
+func NewDispatcher(key string, httpClient external.Doer, limiter ratelimit.Limiter, opts ...Option) *Dispatcher {
+	return &Dispatcher{c: New(key, httpClient, limiter, opts...).(*client)}
+}
+
+// Get calls the method on the given platform, and unmarshals the response
+// into dest. The method is the relative method path with all options
+// stripped, e.g. "/tft/summoner/v1/summoners/by-puuid", and the relativePath
+// is appended to it to form the REST endpoint. The method and uniquifier
+// identify the ratelimit method bucket. On failure, an *APIError is returned
+// as documented for Client.
+func (d *Dispatcher) Get(ctx context.Context, r region.Region, m string, relativePath string, v url.Values, uniquifier string, dest interface{}) error {
+	_, err := d.c.sendAndUnmarshal(ctx, http.MethodGet, d.c.baseURL(string(r), r.Host), string(r), m, relativePath, v, uniquifier, nil, dest)
+	return err
+}
+
+// GetRouting is the same as Get, except that the method is served by the
+// given regional cluster instead of by a platform.
+func (d *Dispatcher) GetRouting(ctx context.Context, r region.Routing, m string, relativePath string, v url.Values, uniquifier string, dest interface{}) error {
+	_, err := d.c.sendAndUnmarshal(ctx, http.MethodGet, d.c.baseURL(string(r), r.Host), string(r), m, relativePath, v, uniquifier, nil, dest)
+	return err
+}
@@ -1,6 +1,14 @@
 package apiclient
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
 
 var (
 	ErrBadRequest           = errors.New("bad request")
@@ -17,6 +25,15 @@ var (
 
 	ErrBadHTTPStatus = errors.New("bad HTTP status returned by server")
 
+	ErrBadRiotID = errors.New("riot ID must have the form gameName#tagLine")
+
+	// ErrNotSupportedByStub is returned by tournament stub methods that have
+	// no stub endpoint.
+	ErrNotSupportedByStub = errors.New("method is not supported by the tournament stub")
+
+	// ErrIteratorDone is returned by iterators when there are no more items.
+	ErrIteratorDone = errors.New("no more items in iterator")
+
 	httpErrors = map[int]error{
 		400: ErrBadRequest,
 		401: ErrUnauthorized,
@@ -31,3 +48,80 @@ var (
 		504: ErrGatewayTimeout,
 	}
 )
+
+// APIError is returned by Client methods when the Riot API responds with a
+// status other than HTTP OK. It wraps one of the sentinel errors above, so
+// callers can continue to test for specific statuses with errors.Is, for
+// example errors.Is(err, ErrDataNotFound).
+type APIError struct {
+	// StatusCode is the HTTP status returned by the API.
+	StatusCode int
+
+	// Method is the relative method path with all options stripped, for example
+	// "/lol/match/v5/matches".
+	Method string
+
+	// Region is the platform or regional routing value that served the call.
+	Region string
+
+	// Message is the error message returned by Riot in the response body, if
+	// any.
+	Message string
+
+	// Body is the raw response body.
+	Body []byte
+
+	// RateLimitType is the X-Rate-Limit-Type header, which is set on HTTP 429
+	// responses to one of "application", "method" or "service".
+	RateLimitType string
+
+	// RetryAfter is the delay requested by the Retry-After header, or zero if
+	// the header was not set.
+	RetryAfter time.Duration
+}
+
+// newAPIError returns an APIError describing the response, which must have a
+// non-OK status. The body must already have been read.
+func newAPIError(res *http.Response, method, region string, body []byte) *APIError {
+	e := &APIError{
+		StatusCode:    res.StatusCode,
+		Method:        method,
+		Region:        region,
+		Body:          body,
+		RateLimitType: strings.TrimSpace(res.Header.Get("X-Rate-Limit-Type")),
+	}
+	if seconds, err := strconv.ParseInt(strings.TrimSpace(res.Header.Get("Retry-After")), 10, 64); err == nil {
+		e.RetryAfter = time.Duration(seconds) * time.Second
+	}
+
+	// Riot error bodies look like {"status": {"message": "...", "status_code": 404}}.
+	var riotErr struct {
+		Status struct {
+			Message string `json:"message"`
+		} `json:"status"`
+	}
+	if json.Unmarshal(body, &riotErr) == nil {
+		e.Message = riotErr.Status.Message
+	}
+	return e
+}
+
+// Unwrap returns the sentinel error corresponding to the HTTP status, or
+// ErrBadHTTPStatus if the status is not one documented by Riot.
+func (e *APIError) Unwrap() error {
+	if err, ok := httpErrors[e.StatusCode]; ok {
+		return err
+	}
+	return ErrBadHTTPStatus
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: HTTP %d: %v", e.Region, e.Method, e.StatusCode, e.Unwrap())
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.RateLimitType != "" {
+		msg += fmt.Sprintf(" (%s rate limit, retry after %v)", e.RateLimitType, e.RetryAfter)
+	}
+	return msg
+}
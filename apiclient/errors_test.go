@@ -0,0 +1,56 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/testing/doertest"
+)
+
+func TestAPIError(t *testing.T) {
+	header := make(http.Header)
+	header.Set("Retry-After", "7")
+	header.Set("X-Rate-Limit-Type", "method")
+	body := `{"status": {"message": "Rate limit exceeded", "status_code": 429}}`
+	c := New("key", doertest.StaticResponse(http.StatusTooManyRequests, header, body), ratelimit.NewLimiter())
+
+	_, err := c.GetBySummonerPUUID(context.Background(), region.NA1, "puuid")
+	if !errors.Is(err, ErrRateLimitExceeded) {
+		t.Fatalf("errors.Is(%v, ErrRateLimitExceeded) = false, want true", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("got error of type %T, want *APIError", err)
+	}
+	want := APIError{
+		StatusCode:    http.StatusTooManyRequests,
+		Method:        "/lol/summoner/v4/summoners/by-puuid",
+		Region:        "NA1",
+		Message:       "Rate limit exceeded",
+		Body:          []byte(body),
+		RateLimitType: "method",
+		RetryAfter:    7 * time.Second,
+	}
+	if apiErr.StatusCode != want.StatusCode || apiErr.Method != want.Method || apiErr.Region != want.Region ||
+		apiErr.Message != want.Message || string(apiErr.Body) != string(want.Body) ||
+		apiErr.RateLimitType != want.RateLimitType || apiErr.RetryAfter != want.RetryAfter {
+		t.Errorf("got %+v, want %+v", *apiErr, want)
+	}
+}
+
+func TestAPIErrorUnknownStatus(t *testing.T) {
+	c := New("key", doertest.StaticResponse(418, nil, "teapot"), ratelimit.NewLimiter())
+	_, err := c.GetMatchV5(context.Background(), region.EUW1, "EUW1_1")
+	if !errors.Is(err, ErrBadHTTPStatus) {
+		t.Fatalf("errors.Is(%v, ErrBadHTTPStatus) = false, want true", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Region != "EUROPE" || apiErr.Message != "" {
+		t.Errorf("got %+v, want EUROPE region with no message", apiErr)
+	}
+}
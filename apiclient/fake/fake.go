@@ -0,0 +1,751 @@
+// Package fake implements an in-memory apiclient.Client for tests.
+//
+// A fake Client serves data from a Dataset instead of calling the Riot API, so
+// code that depends on apiclient.Client can be tested offline. Data that is
+// missing from the Dataset is reported as an *apiclient.APIError with HTTP
+// status 404, just like the real client, so errors.Is(err,
+// apiclient.ErrDataNotFound) works as expected. Errors and latency can be
+// injected per method with SetError and SetLatency.
+//
+// Use the New() constructor to initialize a Client.
+package fake
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/constants/challenge"
+	"github.com/yuhanfang/riot/constants/champion"
+	"github.com/yuhanfang/riot/constants/division"
+	"github.com/yuhanfang/riot/constants/game"
+	"github.com/yuhanfang/riot/constants/queue"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/constants/tier"
+)
+
+// Dataset is the data served by a fake Client. Maps are keyed by platform,
+// and then by the identifier used to look up the value, if any. The Dataset
+// can be marshaled to and from JSON, which allows it to be stored as a test
+// fixture.
+type Dataset struct {
+	Accounts     []apiclient.Account                    `json:"accounts"`
+	ActiveShards []apiclient.ActiveShard                `json:"activeShards"`
+	Summoners    map[region.Region][]apiclient.Summoner `json:"summoners"`
+
+	// ChallengeConfigs are the challenges of each platform.
+	ChallengeConfigs map[region.Region][]apiclient.ChallengeConfig `json:"challengeConfigs"`
+
+	// ChallengePercentiles maps platform to challenge ID to level to the
+	// fraction of players that have reached the level.
+	ChallengePercentiles map[region.Region]map[int64]map[challenge.Level]float64 `json:"challengePercentiles"`
+
+	// ChallengePlayers maps platform to PUUID to challenge progress.
+	// Leaderboards are derived from the progress of all players.
+	ChallengePlayers map[region.Region]map[string]apiclient.PlayerChallenges `json:"challengePlayers"`
+
+	// ChampionMasteries maps platform to summoner ID to masteries.
+	ChampionMasteries map[region.Region]map[string][]apiclient.ChampionMastery `json:"championMasteries"`
+	Champions         map[region.Region][]apiclient.Champion                   `json:"champions"`
+	ChampionRotations map[region.Region]apiclient.ChampionRotation             `json:"championRotations"`
+
+	// ClashPlayers are the Clash registrations for each platform. Clash teams
+	// and tournaments are found by ID.
+	ClashPlayers     map[region.Region][]apiclient.ClashPlayer     `json:"clashPlayers"`
+	ClashTeams       map[region.Region][]apiclient.ClashTeam       `json:"clashTeams"`
+	ClashTournaments map[region.Region][]apiclient.ClashTournament `json:"clashTournaments"`
+
+	// Leagues are the leagues for each platform. Challenger, grandmaster and
+	// master leagues are found by tier and queue.
+	Leagues map[region.Region][]apiclient.LeagueList `json:"leagues"`
+
+	// LeaguePositions maps platform to summoner ID to league positions.
+	LeaguePositions map[region.Region]map[string][]apiclient.LeaguePosition `json:"leaguePositions"`
+
+	// Matches are match-v4 matches. Matchlists are derived from the account IDs
+	// of the match participants.
+	Matches map[region.Region][]apiclient.Match `json:"matches"`
+
+	// Timelines maps platform to match-v4 game ID to timeline.
+	Timelines map[region.Region]map[int64]apiclient.MatchTimeline `json:"timelines"`
+
+	// MatchesV5 are match-v5 matches, which are unique by match ID across all
+	// platforms. Match ID lists are derived from the participant PUUIDs.
+	MatchesV5 []apiclient.MatchV5 `json:"matchesV5"`
+
+	// TimelinesV5 are match-v5 timelines, identified by Metadata.MatchID.
+	TimelinesV5 []apiclient.MatchTimelineV5 `json:"timelinesV5"`
+
+	FeaturedGames map[region.Region]apiclient.FeaturedGames `json:"featuredGames"`
+
+	// CurrentGames maps platform to summoner ID to the game in progress.
+	CurrentGames map[region.Region]map[string]apiclient.CurrentGameInfo `json:"currentGames"`
+
+	PlatformStatus map[region.Region]apiclient.PlatformStatus `json:"platformStatus"`
+
+	// ThirdPartyCodes maps platform to summoner ID to third party code.
+	ThirdPartyCodes map[region.Region]map[string]string `json:"thirdPartyCodes"`
+}
+
+// Client is an in-memory implementation of apiclient.Client. It is illegal
+// to construct an instance directly. Use New() to return a valid instance. The
+// Client is threadsafe.
+type Client struct {
+	d *Dataset
+
+	lock    sync.RWMutex
+	errors  map[string]error
+	latency map[string]time.Duration
+}
+
+var _ apiclient.Client = (*Client)(nil)
+
+// New returns a Client that serves the given dataset. The dataset must not be
+// modified after it is passed to New.
+func New(d *Dataset) *Client {
+	if d == nil {
+		d = &Dataset{}
+	}
+	return &Client{
+		d:       d,
+		errors:  make(map[string]error),
+		latency: make(map[string]time.Duration),
+	}
+}
+
+// SetError causes every call to the given apiclient.Client method, e.g.
+// "GetMatch", to fail with the given error. A nil error clears the injected
+// error.
+func (c *Client) SetError(method string, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err == nil {
+		delete(c.errors, method)
+		return
+	}
+	c.errors[method] = err
+}
+
+// SetLatency delays every call to the given apiclient.Client method, e.g.
+// "GetMatch", by the given duration. Delayed calls return early with the
+// context error if the context is cancelled. A zero duration clears the
+// injected latency.
+func (c *Client) SetLatency(method string, d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if d == 0 {
+		delete(c.latency, method)
+		return
+	}
+	c.latency[method] = d
+}
+
+// call applies injected latency and errors for the given method.
+func (c *Client) call(ctx context.Context, method string) error {
+	c.lock.RLock()
+	err := c.errors[method]
+	d := c.latency[method]
+	c.lock.RUnlock()
+
+	if d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	return err
+}
+
+// notFound returns the error returned by the API when data does not exist.
+func notFound(r, method string) error {
+	return &apiclient.APIError{
+		StatusCode: http.StatusNotFound,
+		Method:     method,
+		Region:     strings.ToUpper(r),
+		Message:    "Data not found",
+	}
+}
+
+func (c *Client) GetAccountByPUUID(ctx context.Context, r region.Routing, puuid string) (*apiclient.Account, error) {
+	if err := c.call(ctx, "GetAccountByPUUID"); err != nil {
+		return nil, err
+	}
+	for _, a := range c.d.Accounts {
+		if a.PUUID == puuid {
+			return &a, nil
+		}
+	}
+	return nil, notFound(string(r), "/riot/account/v1/accounts/by-puuid")
+}
+
+func (c *Client) GetAccountByRiotID(ctx context.Context, r region.Routing, gameName, tagLine string) (*apiclient.Account, error) {
+	if err := c.call(ctx, "GetAccountByRiotID"); err != nil {
+		return nil, err
+	}
+	// Riot IDs are case insensitive.
+	for _, a := range c.d.Accounts {
+		if strings.EqualFold(a.GameName, gameName) && strings.EqualFold(a.TagLine, tagLine) {
+			return &a, nil
+		}
+	}
+	return nil, notFound(string(r), "/riot/account/v1/accounts/by-riot-id")
+}
+
+func (c *Client) GetActiveShard(ctx context.Context, r region.Routing, g game.Game, puuid string) (*apiclient.ActiveShard, error) {
+	if err := c.call(ctx, "GetActiveShard"); err != nil {
+		return nil, err
+	}
+	for _, s := range c.d.ActiveShards {
+		if s.PUUID == puuid && s.Game == g {
+			return &s, nil
+		}
+	}
+	return nil, notFound(string(r), "/riot/account/v1/active-shards/by-game")
+}
+
+func (c *Client) GetChallengeConfigs(ctx context.Context, r region.Region) ([]apiclient.ChallengeConfig, error) {
+	if err := c.call(ctx, "GetChallengeConfigs"); err != nil {
+		return nil, err
+	}
+	return append([]apiclient.ChallengeConfig{}, c.d.ChallengeConfigs[r]...), nil
+}
+
+func (c *Client) GetChallengePercentiles(ctx context.Context, r region.Region) (map[int64]map[challenge.Level]float64, error) {
+	if err := c.call(ctx, "GetChallengePercentiles"); err != nil {
+		return nil, err
+	}
+	res := make(map[int64]map[challenge.Level]float64)
+	for id, p := range c.d.ChallengePercentiles[r] {
+		res[id] = p
+	}
+	return res, nil
+}
+
+func (c *Client) GetChallengeConfig(ctx context.Context, r region.Region, challengeID int64) (*apiclient.ChallengeConfig, error) {
+	if err := c.call(ctx, "GetChallengeConfig"); err != nil {
+		return nil, err
+	}
+	for _, config := range c.d.ChallengeConfigs[r] {
+		if config.ID == challengeID {
+			return &config, nil
+		}
+	}
+	return nil, notFound(string(r), "/lol/challenges/v1/challenges")
+}
+
+func (c *Client) GetChallengePercentilesByID(ctx context.Context, r region.Region, challengeID int64) (map[challenge.Level]float64, error) {
+	if err := c.call(ctx, "GetChallengePercentilesByID"); err != nil {
+		return nil, err
+	}
+	if p, ok := c.d.ChallengePercentiles[r][challengeID]; ok {
+		return p, nil
+	}
+	return nil, notFound(string(r), "/lol/challenges/v1/challenges")
+}
+
+// GetChallengeLeaderboard returns the players at the given level, sorted by
+// value descending and then by PUUID.
+func (c *Client) GetChallengeLeaderboard(ctx context.Context, r region.Region, challengeID int64, level challenge.Level, limit int) ([]apiclient.ChallengeApexPlayer, error) {
+	if err := c.call(ctx, "GetChallengeLeaderboard"); err != nil {
+		return nil, err
+	}
+	res := []apiclient.ChallengeApexPlayer{}
+	for puuid, p := range c.d.ChallengePlayers[r] {
+		for _, progress := range p.Challenges {
+			if progress.ChallengeID == challengeID && progress.Level == level {
+				res = append(res, apiclient.ChallengeApexPlayer{PUUID: puuid, Value: progress.Value})
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Value != res[j].Value {
+			return res[i].Value > res[j].Value
+		}
+		return res[i].PUUID < res[j].PUUID
+	})
+	if limit > 0 && len(res) > limit {
+		res = res[:limit]
+	}
+	for i := range res {
+		res[i].Position = i + 1
+	}
+	return res, nil
+}
+
+func (c *Client) GetPlayerChallenges(ctx context.Context, r region.Region, puuid string) (*apiclient.PlayerChallenges, error) {
+	if err := c.call(ctx, "GetPlayerChallenges"); err != nil {
+		return nil, err
+	}
+	if p, ok := c.d.ChallengePlayers[r][puuid]; ok {
+		return &p, nil
+	}
+	return nil, notFound(string(r), "/lol/challenges/v1/player-data")
+}
+
+func (c *Client) GetAllChampionMasteries(ctx context.Context, r region.Region, summonerID string) ([]apiclient.ChampionMastery, error) {
+	if err := c.call(ctx, "GetAllChampionMasteries"); err != nil {
+		return nil, err
+	}
+	masteries, ok := c.d.ChampionMasteries[r][summonerID]
+	if !ok {
+		return nil, notFound(string(r), "/lol/champion-mastery/v4/champion-masteries/by-summoner")
+	}
+	return append([]apiclient.ChampionMastery(nil), masteries...), nil
+}
+
+func (c *Client) GetChampionMastery(ctx context.Context, r region.Region, summonerID string, champ champion.Champion) (*apiclient.ChampionMastery, error) {
+	if err := c.call(ctx, "GetChampionMastery"); err != nil {
+		return nil, err
+	}
+	for _, m := range c.d.ChampionMasteries[r][summonerID] {
+		if m.ChampionID == champ {
+			return &m, nil
+		}
+	}
+	return nil, notFound(string(r), "/lol/champion-mastery/v4/champion-masteries/by-summoner")
+}
+
+func (c *Client) GetChampionMasteryScore(ctx context.Context, r region.Region, summonerID string) (int, error) {
+	if err := c.call(ctx, "GetChampionMasteryScore"); err != nil {
+		return 0, err
+	}
+	var score int
+	for _, m := range c.d.ChampionMasteries[r][summonerID] {
+		score += m.ChampionLevel
+	}
+	return score, nil
+}
+
+// masteriesByPUUID returns the champion masteries of the given PUUID, which
+// are either tagged with the PUUID, or belong to the summoner with the PUUID.
+func (c *Client) masteriesByPUUID(r region.Region, puuid string) ([]apiclient.ChampionMastery, bool) {
+	for _, masteries := range c.d.ChampionMasteries[r] {
+		if len(masteries) > 0 && masteries[0].PUUID == puuid {
+			return masteries, true
+		}
+	}
+	for _, s := range c.d.Summoners[r] {
+		if s.PUUID == puuid {
+			masteries, ok := c.d.ChampionMasteries[r][s.ID]
+			return masteries, ok
+		}
+	}
+	return nil, false
+}
+
+func (c *Client) GetAllChampionMasteriesByPUUID(ctx context.Context, r region.Region, puuid string) ([]apiclient.ChampionMastery, error) {
+	if err := c.call(ctx, "GetAllChampionMasteriesByPUUID"); err != nil {
+		return nil, err
+	}
+	masteries, ok := c.masteriesByPUUID(r, puuid)
+	if !ok {
+		return nil, notFound(string(r), "/lol/champion-mastery/v4/champion-masteries/by-puuid")
+	}
+	return append([]apiclient.ChampionMastery(nil), masteries...), nil
+}
+
+func (c *Client) GetChampionMasteryByPUUID(ctx context.Context, r region.Region, puuid string, champ champion.Champion) (*apiclient.ChampionMastery, error) {
+	if err := c.call(ctx, "GetChampionMasteryByPUUID"); err != nil {
+		return nil, err
+	}
+	masteries, _ := c.masteriesByPUUID(r, puuid)
+	for _, m := range masteries {
+		if m.ChampionID == champ {
+			return &m, nil
+		}
+	}
+	return nil, notFound(string(r), "/lol/champion-mastery/v4/champion-masteries/by-puuid")
+}
+
+// defaultTopMasteries is the number of masteries returned by
+// GetTopChampionMasteries when no count is given.
+const defaultTopMasteries = 3
+
+func (c *Client) GetTopChampionMasteries(ctx context.Context, r region.Region, puuid string, count int) ([]apiclient.ChampionMastery, error) {
+	if err := c.call(ctx, "GetTopChampionMasteries"); err != nil {
+		return nil, err
+	}
+	masteries, ok := c.masteriesByPUUID(r, puuid)
+	if !ok {
+		return nil, notFound(string(r), "/lol/champion-mastery/v4/champion-masteries/by-puuid")
+	}
+	res := append([]apiclient.ChampionMastery(nil), masteries...)
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].ChampionPoints > res[j].ChampionPoints
+	})
+	if count <= 0 {
+		count = defaultTopMasteries
+	}
+	if len(res) > count {
+		res = res[:count]
+	}
+	return res, nil
+}
+
+func (c *Client) GetChampionMasteryScoreByPUUID(ctx context.Context, r region.Region, puuid string) (int, error) {
+	if err := c.call(ctx, "GetChampionMasteryScoreByPUUID"); err != nil {
+		return 0, err
+	}
+	masteries, _ := c.masteriesByPUUID(r, puuid)
+	var score int
+	for _, m := range masteries {
+		score += m.ChampionLevel
+	}
+	return score, nil
+}
+
+func (c *Client) GetChampions(ctx context.Context, r region.Region) (*apiclient.ChampionList, error) {
+	if err := c.call(ctx, "GetChampions"); err != nil {
+		return nil, err
+	}
+	return &apiclient.ChampionList{Champions: append([]apiclient.Champion(nil), c.d.Champions[r]...)}, nil
+}
+
+func (c *Client) GetChampionByID(ctx context.Context, r region.Region, champ champion.Champion) (*apiclient.Champion, error) {
+	if err := c.call(ctx, "GetChampionByID"); err != nil {
+		return nil, err
+	}
+	for _, ch := range c.d.Champions[r] {
+		if ch.ID == int64(champ) {
+			return &ch, nil
+		}
+	}
+	return nil, notFound(string(r), "/lol/platform/v3/champions")
+}
+
+func (c *Client) GetChampionRotations(ctx context.Context, r region.Region) (*apiclient.ChampionRotation, error) {
+	if err := c.call(ctx, "GetChampionRotations"); err != nil {
+		return nil, err
+	}
+	rotation, ok := c.d.ChampionRotations[r]
+	if !ok {
+		return nil, notFound(string(r), "/lol/platform/v3/champion-rotations")
+	}
+	return &rotation, nil
+}
+
+// clashPlayers returns the Clash registrations that match.
+func (c *Client) clashPlayers(r region.Region, match func(*apiclient.ClashPlayer) bool) []apiclient.ClashPlayer {
+	// Players without registrations are not an error.
+	res := []apiclient.ClashPlayer{}
+	for _, p := range c.d.ClashPlayers[r] {
+		if match(&p) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+func (c *Client) GetClashPlayersBySummoner(ctx context.Context, r region.Region, summonerID string) ([]apiclient.ClashPlayer, error) {
+	if err := c.call(ctx, "GetClashPlayersBySummoner"); err != nil {
+		return nil, err
+	}
+	return c.clashPlayers(r, func(p *apiclient.ClashPlayer) bool {
+		return p.SummonerID == summonerID
+	}), nil
+}
+
+func (c *Client) GetClashPlayersByPUUID(ctx context.Context, r region.Region, puuid string) ([]apiclient.ClashPlayer, error) {
+	if err := c.call(ctx, "GetClashPlayersByPUUID"); err != nil {
+		return nil, err
+	}
+	return c.clashPlayers(r, func(p *apiclient.ClashPlayer) bool {
+		return p.PUUID == puuid
+	}), nil
+}
+
+func (c *Client) clashTeam(r region.Region, teamID string) (*apiclient.ClashTeam, bool) {
+	for _, t := range c.d.ClashTeams[r] {
+		if t.ID == teamID {
+			return &t, true
+		}
+	}
+	return nil, false
+}
+
+func (c *Client) clashTournament(r region.Region, tournamentID int) (*apiclient.ClashTournament, bool) {
+	for _, t := range c.d.ClashTournaments[r] {
+		if t.ID == tournamentID {
+			return &t, true
+		}
+	}
+	return nil, false
+}
+
+func (c *Client) GetClashTeam(ctx context.Context, r region.Region, teamID string) (*apiclient.ClashTeam, error) {
+	if err := c.call(ctx, "GetClashTeam"); err != nil {
+		return nil, err
+	}
+	if t, ok := c.clashTeam(r, teamID); ok {
+		return t, nil
+	}
+	return nil, notFound(string(r), "/lol/clash/v1/teams")
+}
+
+// GetClashTournaments returns all tournaments in the dataset, which are
+// considered active or upcoming.
+func (c *Client) GetClashTournaments(ctx context.Context, r region.Region) ([]apiclient.ClashTournament, error) {
+	if err := c.call(ctx, "GetClashTournaments"); err != nil {
+		return nil, err
+	}
+	return append([]apiclient.ClashTournament{}, c.d.ClashTournaments[r]...), nil
+}
+
+func (c *Client) GetClashTournamentByTeam(ctx context.Context, r region.Region, teamID string) (*apiclient.ClashTournament, error) {
+	if err := c.call(ctx, "GetClashTournamentByTeam"); err != nil {
+		return nil, err
+	}
+	if team, ok := c.clashTeam(r, teamID); ok {
+		if t, ok := c.clashTournament(r, team.TournamentID); ok {
+			return t, nil
+		}
+	}
+	return nil, notFound(string(r), "/lol/clash/v1/tournaments/by-team")
+}
+
+func (c *Client) GetClashTournament(ctx context.Context, r region.Region, tournamentID int) (*apiclient.ClashTournament, error) {
+	if err := c.call(ctx, "GetClashTournament"); err != nil {
+		return nil, err
+	}
+	if t, ok := c.clashTournament(r, tournamentID); ok {
+		return t, nil
+	}
+	return nil, notFound(string(r), "/lol/clash/v1/tournaments")
+}
+
+// leagueByTier returns the league in the given tier and queue.
+func (c *Client) leagueByTier(r region.Region, t tier.Tier, q queue.Queue, method string) (*apiclient.LeagueList, error) {
+	for _, l := range c.d.Leagues[r] {
+		if l.Tier == t && l.Queue == q {
+			return &l, nil
+		}
+	}
+	return nil, notFound(string(r), method)
+}
+
+func (c *Client) GetChallengerLeague(ctx context.Context, r region.Region, q queue.Queue) (*apiclient.LeagueList, error) {
+	if err := c.call(ctx, "GetChallengerLeague"); err != nil {
+		return nil, err
+	}
+	return c.leagueByTier(r, tier.Challenger, q, "/lol/league/v4/challengerleagues/by-queue")
+}
+
+func (c *Client) GetGrandmasterLeague(ctx context.Context, r region.Region, q queue.Queue) (*apiclient.LeagueList, error) {
+	if err := c.call(ctx, "GetGrandmasterLeague"); err != nil {
+		return nil, err
+	}
+	return c.leagueByTier(r, tier.Grandmaster, q, "/lol/league/v4/grandmasterleagues/by-queue")
+}
+
+func (c *Client) GetMasterLeague(ctx context.Context, r region.Region, q queue.Queue) (*apiclient.LeagueList, error) {
+	if err := c.call(ctx, "GetMasterLeague"); err != nil {
+		return nil, err
+	}
+	return c.leagueByTier(r, tier.Master, q, "/lol/league/v4/masterleagues/by-queue")
+}
+
+func (c *Client) GetAllLeaguePositionsForSummoner(ctx context.Context, r region.Region, summonerID string) ([]apiclient.LeaguePosition, error) {
+	if err := c.call(ctx, "GetAllLeaguePositionsForSummoner"); err != nil {
+		return nil, err
+	}
+	// Unranked summoners have no positions, which is not an error.
+	return append([]apiclient.LeaguePosition(nil), c.d.LeaguePositions[r][summonerID]...), nil
+}
+
+func (c *Client) GetLeagueByID(ctx context.Context, r region.Region, leagueID string) (*apiclient.LeagueList, error) {
+	if err := c.call(ctx, "GetLeagueByID"); err != nil {
+		return nil, err
+	}
+	for _, l := range c.d.Leagues[r] {
+		if l.LeagueID == leagueID {
+			return &l, nil
+		}
+	}
+	return nil, notFound(string(r), "/lol/league/v4/leagues")
+}
+
+// leagueEntriesPageSize is the number of entries per page returned by
+// GetLeagueEntries and GetLeagueExpEntries.
+const leagueEntriesPageSize = 205
+
+// leagueEntries returns a page of the league positions in the dataset with
+// the given queue, tier and division, sorted by league points descending.
+func (c *Client) leagueEntries(r region.Region, q queue.Queue, t tier.Tier, d division.Division, page int) []apiclient.LeaguePosition {
+	var entries []apiclient.LeaguePosition
+	for summonerID, positions := range c.d.LeaguePositions[r] {
+		for _, p := range positions {
+			if p.QueueType != q.String() || p.Tier != t || p.Rank != string(d) {
+				continue
+			}
+			if p.SummonerID == "" {
+				p.SummonerID = summonerID
+			}
+			entries = append(entries, p)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].LeaguePoints != entries[j].LeaguePoints {
+			return entries[i].LeaguePoints > entries[j].LeaguePoints
+		}
+		return entries[i].SummonerID < entries[j].SummonerID
+	})
+	if page < 1 {
+		page = 1
+	}
+	// An empty page is not an error.
+	res := []apiclient.LeaguePosition{}
+	for i := (page - 1) * leagueEntriesPageSize; i < len(entries) && i < page*leagueEntriesPageSize; i++ {
+		res = append(res, entries[i])
+	}
+	return res
+}
+
+func (c *Client) GetLeagueEntries(ctx context.Context, r region.Region, q queue.Queue, t tier.Tier, d division.Division, page int) ([]apiclient.LeaguePosition, error) {
+	if err := c.call(ctx, "GetLeagueEntries"); err != nil {
+		return nil, err
+	}
+	return c.leagueEntries(r, q, t, d, page), nil
+}
+
+func (c *Client) GetLeagueExpEntries(ctx context.Context, r region.Region, q queue.Queue, t tier.Tier, d division.Division, page int) ([]apiclient.LeaguePosition, error) {
+	if err := c.call(ctx, "GetLeagueExpEntries"); err != nil {
+		return nil, err
+	}
+	return c.leagueEntries(r, q, t, d, page), nil
+}
+
+func (c *Client) GetFeaturedGames(ctx context.Context, r region.Region) (*apiclient.FeaturedGames, error) {
+	if err := c.call(ctx, "GetFeaturedGames"); err != nil {
+		return nil, err
+	}
+	games := c.d.FeaturedGames[r]
+	return &games, nil
+}
+
+func (c *Client) GetCurrentGameInfoBySummoner(ctx context.Context, r region.Region, summonerID string) (*apiclient.CurrentGameInfo, error) {
+	if err := c.call(ctx, "GetCurrentGameInfoBySummoner"); err != nil {
+		return nil, err
+	}
+	info, ok := c.d.CurrentGames[r][summonerID]
+	if !ok {
+		return nil, notFound(string(r), "/lol/spectator/v4/active-games/by-summoner")
+	}
+	return &info, nil
+}
+
+func (c *Client) GetFeaturedGamesV5(ctx context.Context, r region.Region) (*apiclient.FeaturedGames, error) {
+	if err := c.call(ctx, "GetFeaturedGamesV5"); err != nil {
+		return nil, err
+	}
+	games := c.d.FeaturedGames[r]
+	return &games, nil
+}
+
+// GetCurrentGameInfoByPUUID returns the game in progress that has a
+// participant with the given PUUID, or the game of the summoner with the
+// given PUUID.
+func (c *Client) GetCurrentGameInfoByPUUID(ctx context.Context, r region.Region, puuid string) (*apiclient.CurrentGameInfo, error) {
+	if err := c.call(ctx, "GetCurrentGameInfoByPUUID"); err != nil {
+		return nil, err
+	}
+	for _, info := range c.d.CurrentGames[r] {
+		for _, p := range info.Participants {
+			if p.PUUID == puuid {
+				return &info, nil
+			}
+		}
+	}
+	for _, s := range c.d.Summoners[r] {
+		if s.PUUID != puuid {
+			continue
+		}
+		if info, ok := c.d.CurrentGames[r][s.ID]; ok {
+			return &info, nil
+		}
+	}
+	return nil, notFound(string(r), "/lol/spectator/v5/active-games/by-summoner")
+}
+
+// GetPlatformStatus returns the platform status in the dataset, or a status
+// without maintenances and incidents if the dataset has none for the platform.
+func (c *Client) GetPlatformStatus(ctx context.Context, r region.Region) (*apiclient.PlatformStatus, error) {
+	if err := c.call(ctx, "GetPlatformStatus"); err != nil {
+		return nil, err
+	}
+	status, ok := c.d.PlatformStatus[r]
+	if !ok {
+		status = apiclient.PlatformStatus{ID: string(r), Name: string(r)}
+	}
+	return &status, nil
+}
+
+// summoner returns the first summoner in the platform that matches.
+func (c *Client) summoner(r region.Region, method string, match func(*apiclient.Summoner) bool) (*apiclient.Summoner, error) {
+	for _, s := range c.d.Summoners[r] {
+		if match(&s) {
+			return &s, nil
+		}
+	}
+	return nil, notFound(string(r), method)
+}
+
+func (c *Client) GetByAccountID(ctx context.Context, r region.Region, accountID string) (*apiclient.Summoner, error) {
+	if err := c.call(ctx, "GetByAccountID"); err != nil {
+		return nil, err
+	}
+	return c.summoner(r, "/lol/summoner/v4/summoners/by-account", func(s *apiclient.Summoner) bool {
+		return s.AccountID == accountID
+	})
+}
+
+func (c *Client) GetBySummonerName(ctx context.Context, r region.Region, name string) (*apiclient.Summoner, error) {
+	if err := c.call(ctx, "GetBySummonerName"); err != nil {
+		return nil, err
+	}
+	// Summoner names ignore case and whitespace.
+	normalize := func(s string) string {
+		return strings.ToLower(strings.Join(strings.Fields(s), ""))
+	}
+	return c.summoner(r, "/lol/summoner/v4/summoners/by-name", func(s *apiclient.Summoner) bool {
+		return normalize(s.Name) == normalize(name)
+	})
+}
+
+func (c *Client) GetBySummonerPUUID(ctx context.Context, r region.Region, puuid string) (*apiclient.Summoner, error) {
+	if err := c.call(ctx, "GetBySummonerPUUID"); err != nil {
+		return nil, err
+	}
+	return c.summoner(r, "/lol/summoner/v4/summoners/by-puuid", func(s *apiclient.Summoner) bool {
+		return s.PUUID == puuid
+	})
+}
+
+func (c *Client) GetBySummonerID(ctx context.Context, r region.Region, summonerID string) (*apiclient.Summoner, error) {
+	if err := c.call(ctx, "GetBySummonerID"); err != nil {
+		return nil, err
+	}
+	return c.summoner(r, "/lol/summoner/v4/summoners", func(s *apiclient.Summoner) bool {
+		return s.ID == summonerID
+	})
+}
+
+func (c *Client) GetThirdPartyCodeByID(ctx context.Context, r region.Region, summonerID string) (string, error) {
+	if err := c.call(ctx, "GetThirdPartyCodeByID"); err != nil {
+		return "", err
+	}
+	code, ok := c.d.ThirdPartyCodes[r][summonerID]
+	if !ok {
+		return "", notFound(string(r), "/lol/platform/v4/third-party-code/by-summoner")
+	}
+	return code, nil
+}
@@ -0,0 +1,148 @@
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/constants/champion"
+	"github.com/yuhanfang/riot/constants/division"
+	"github.com/yuhanfang/riot/constants/queue"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/constants/tier"
+	"github.com/yuhanfang/riot/types"
+)
+
+// testMatch returns a match-v4 match with a single participant.
+func testMatch(gameID int64, accountID string, q queue.Queue, champ champion.Champion, created time.Time) apiclient.Match {
+	return apiclient.Match{
+		GameID:       gameID,
+		QueueID:      q,
+		GameCreation: types.Milliseconds(created.UnixNano() / int64(time.Millisecond)),
+		ParticipantIdentities: []apiclient.ParticipantIdentity{
+			{ParticipantID: 1, Player: apiclient.Player{AccountID: accountID}},
+		},
+		Participants: []apiclient.Participant{
+			{ParticipantID: 1, ChampionID: champ},
+		},
+	}
+}
+
+func testDataset() *Dataset {
+	now := time.Date(2019, time.January, 10, 0, 0, 0, 0, time.UTC)
+	return &Dataset{
+		Summoners: map[region.Region][]apiclient.Summoner{
+			region.NA1: {{Name: "Waddle Chirp", ID: "summoner", AccountID: "account", PUUID: "puuid"}},
+		},
+		Matches: map[region.Region][]apiclient.Match{
+			region.NA1: {
+				testMatch(1, "account", queue.RankedSolo5x5, champion.Ashe, now.Add(-3*time.Hour)),
+				testMatch(2, "account", queue.RankedFlexSR, champion.Ashe, now.Add(-2*time.Hour)),
+				testMatch(3, "account", queue.RankedSolo5x5, champion.Annie, now.Add(-1*time.Hour)),
+				testMatch(4, "other", queue.RankedSolo5x5, champion.Annie, now),
+			},
+		},
+	}
+}
+
+func TestGetMatchlist(t *testing.T) {
+	c := New(testDataset())
+	ctx := context.Background()
+
+	ml, err := c.GetMatchlist(ctx, region.NA1, "account", &apiclient.GetMatchlistOptions{
+		Queue: []queue.Queue{queue.RankedSolo5x5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []int64
+	for _, m := range ml.Matches {
+		got = append(got, m.GameID)
+	}
+	if fmt.Sprint(got) != "[3 1]" {
+		t.Errorf("got game IDs %v, want [3 1]", got)
+	}
+
+	_, err = c.GetMatchlist(ctx, region.NA1, "missing", nil)
+	if !errors.Is(err, apiclient.ErrDataNotFound) {
+		t.Errorf("got %v, want ErrDataNotFound", err)
+	}
+}
+
+func TestSetErrorAndLatency(t *testing.T) {
+	c := New(testDataset())
+	ctx := context.Background()
+
+	c.SetError("GetBySummonerName", apiclient.ErrServiceUnavailable)
+	if _, err := c.GetBySummonerName(ctx, region.NA1, "waddlechirp"); err != apiclient.ErrServiceUnavailable {
+		t.Errorf("got %v, want injected error", err)
+	}
+	c.SetError("GetBySummonerName", nil)
+	s, err := c.GetBySummonerName(ctx, region.NA1, "waddlechirp")
+	if err != nil || s.ID != "summoner" {
+		t.Errorf("got %+v, %v; want summoner after clearing error", s, err)
+	}
+
+	c.SetLatency("GetBySummonerID", time.Hour)
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	if _, err := c.GetBySummonerID(ctx, region.NA1, "summoner"); err != context.DeadlineExceeded {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func ExampleClient() {
+	c := New(&Dataset{
+		Summoners: map[region.Region][]apiclient.Summoner{
+			region.NA1: {{Name: "waddlechirp", ID: "summoner-id"}},
+		},
+	})
+
+	// c can be used anywhere an apiclient.Client is expected.
+	var client apiclient.Client = c
+	s, err := client.GetBySummonerName(context.Background(), region.NA1, "waddlechirp")
+	fmt.Println(s.ID, err)
+
+	_, err = client.GetBySummonerName(context.Background(), region.NA1, "nobody")
+	fmt.Println(errors.Is(err, apiclient.ErrDataNotFound))
+	// Output:
+	// summoner-id <nil>
+	// true
+}
+
+func TestLeagueEntriesIterator(t *testing.T) {
+	positions := make(map[string][]apiclient.LeaguePosition)
+	for i := 0; i < 300; i++ {
+		id := fmt.Sprintf("summoner%03d", i)
+		positions[id] = []apiclient.LeaguePosition{
+			{QueueType: "RANKED_SOLO_5x5", Tier: tier.Diamond, Rank: "I", LeaguePoints: i},
+			{QueueType: "RANKED_SOLO_5x5", Tier: tier.Diamond, Rank: "II", LeaguePoints: i},
+		}
+	}
+	c := New(&Dataset{
+		LeaguePositions: map[region.Region]map[string][]apiclient.LeaguePosition{region.NA1: positions},
+	})
+	ctx := context.Background()
+
+	it := apiclient.NewLeagueEntriesIterator(c, region.NA1, queue.RankedSolo5x5, tier.Diamond, division.I)
+	var got []string
+	for {
+		e, err := it.Next(ctx)
+		if err == apiclient.ErrIteratorDone {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Rank != "I" {
+			t.Errorf("got rank %q, want I", e.Rank)
+		}
+		got = append(got, e.SummonerID)
+	}
+	if len(got) != 300 || got[0] != "summoner299" || got[299] != "summoner000" {
+		t.Errorf("got %d entries from %v to %v, want 300 from summoner299 to summoner000", len(got), got[0], got[len(got)-1])
+	}
+}
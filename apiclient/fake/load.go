@@ -0,0 +1,193 @@
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/constants/challenge"
+	"github.com/yuhanfang/riot/constants/region"
+)
+
+// LoadDataset reads every .json file in the given directories as a Dataset,
+// and merges them in directory and file name order. This allows fixtures to
+// be split into several files, e.g. one per match.
+func LoadDataset(dirs ...string) (*Dataset, error) {
+	res := &Dataset{}
+	for _, dir := range dirs {
+		files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+		for _, f := range files {
+			b, err := ioutil.ReadFile(f)
+			if err != nil {
+				return nil, err
+			}
+			var d Dataset
+			if err := json.Unmarshal(b, &d); err != nil {
+				return nil, fmt.Errorf("%s: %v", f, err)
+			}
+			res.Merge(&d)
+		}
+	}
+	return res, nil
+}
+
+// Merge adds the data in o to d. Lists are appended, and values that are
+// keyed by platform and identifier replace existing values with the same key.
+func (d *Dataset) Merge(o *Dataset) {
+	d.Accounts = append(d.Accounts, o.Accounts...)
+	d.ActiveShards = append(d.ActiveShards, o.ActiveShards...)
+	d.MatchesV5 = append(d.MatchesV5, o.MatchesV5...)
+	d.TimelinesV5 = append(d.TimelinesV5, o.TimelinesV5...)
+
+	for r, v := range o.Summoners {
+		if d.Summoners == nil {
+			d.Summoners = make(map[region.Region][]apiclient.Summoner)
+		}
+		d.Summoners[r] = append(d.Summoners[r], v...)
+	}
+	for r, v := range o.Champions {
+		if d.Champions == nil {
+			d.Champions = make(map[region.Region][]apiclient.Champion)
+		}
+		d.Champions[r] = append(d.Champions[r], v...)
+	}
+	for r, v := range o.ChallengeConfigs {
+		if d.ChallengeConfigs == nil {
+			d.ChallengeConfigs = make(map[region.Region][]apiclient.ChallengeConfig)
+		}
+		d.ChallengeConfigs[r] = append(d.ChallengeConfigs[r], v...)
+	}
+	for r, v := range o.ClashPlayers {
+		if d.ClashPlayers == nil {
+			d.ClashPlayers = make(map[region.Region][]apiclient.ClashPlayer)
+		}
+		d.ClashPlayers[r] = append(d.ClashPlayers[r], v...)
+	}
+	for r, v := range o.ClashTeams {
+		if d.ClashTeams == nil {
+			d.ClashTeams = make(map[region.Region][]apiclient.ClashTeam)
+		}
+		d.ClashTeams[r] = append(d.ClashTeams[r], v...)
+	}
+	for r, v := range o.ClashTournaments {
+		if d.ClashTournaments == nil {
+			d.ClashTournaments = make(map[region.Region][]apiclient.ClashTournament)
+		}
+		d.ClashTournaments[r] = append(d.ClashTournaments[r], v...)
+	}
+	for r, v := range o.Leagues {
+		if d.Leagues == nil {
+			d.Leagues = make(map[region.Region][]apiclient.LeagueList)
+		}
+		d.Leagues[r] = append(d.Leagues[r], v...)
+	}
+	for r, v := range o.Matches {
+		if d.Matches == nil {
+			d.Matches = make(map[region.Region][]apiclient.Match)
+		}
+		d.Matches[r] = append(d.Matches[r], v...)
+	}
+	for r, v := range o.ChampionRotations {
+		if d.ChampionRotations == nil {
+			d.ChampionRotations = make(map[region.Region]apiclient.ChampionRotation)
+		}
+		d.ChampionRotations[r] = v
+	}
+	for r, v := range o.PlatformStatus {
+		if d.PlatformStatus == nil {
+			d.PlatformStatus = make(map[region.Region]apiclient.PlatformStatus)
+		}
+		d.PlatformStatus[r] = v
+	}
+	for r, v := range o.FeaturedGames {
+		if d.FeaturedGames == nil {
+			d.FeaturedGames = make(map[region.Region]apiclient.FeaturedGames)
+		}
+		d.FeaturedGames[r] = v
+	}
+
+	for r, m := range o.ChallengePercentiles {
+		if d.ChallengePercentiles == nil {
+			d.ChallengePercentiles = make(map[region.Region]map[int64]map[challenge.Level]float64)
+		}
+		if d.ChallengePercentiles[r] == nil {
+			d.ChallengePercentiles[r] = make(map[int64]map[challenge.Level]float64)
+		}
+		for k, v := range m {
+			d.ChallengePercentiles[r][k] = v
+		}
+	}
+	for r, m := range o.ChallengePlayers {
+		if d.ChallengePlayers == nil {
+			d.ChallengePlayers = make(map[region.Region]map[string]apiclient.PlayerChallenges)
+		}
+		if d.ChallengePlayers[r] == nil {
+			d.ChallengePlayers[r] = make(map[string]apiclient.PlayerChallenges)
+		}
+		for k, v := range m {
+			d.ChallengePlayers[r][k] = v
+		}
+	}
+	for r, m := range o.ChampionMasteries {
+		if d.ChampionMasteries == nil {
+			d.ChampionMasteries = make(map[region.Region]map[string][]apiclient.ChampionMastery)
+		}
+		if d.ChampionMasteries[r] == nil {
+			d.ChampionMasteries[r] = make(map[string][]apiclient.ChampionMastery)
+		}
+		for k, v := range m {
+			d.ChampionMasteries[r][k] = v
+		}
+	}
+	for r, m := range o.LeaguePositions {
+		if d.LeaguePositions == nil {
+			d.LeaguePositions = make(map[region.Region]map[string][]apiclient.LeaguePosition)
+		}
+		if d.LeaguePositions[r] == nil {
+			d.LeaguePositions[r] = make(map[string][]apiclient.LeaguePosition)
+		}
+		for k, v := range m {
+			d.LeaguePositions[r][k] = v
+		}
+	}
+	for r, m := range o.Timelines {
+		if d.Timelines == nil {
+			d.Timelines = make(map[region.Region]map[int64]apiclient.MatchTimeline)
+		}
+		if d.Timelines[r] == nil {
+			d.Timelines[r] = make(map[int64]apiclient.MatchTimeline)
+		}
+		for k, v := range m {
+			d.Timelines[r][k] = v
+		}
+	}
+	for r, m := range o.CurrentGames {
+		if d.CurrentGames == nil {
+			d.CurrentGames = make(map[region.Region]map[string]apiclient.CurrentGameInfo)
+		}
+		if d.CurrentGames[r] == nil {
+			d.CurrentGames[r] = make(map[string]apiclient.CurrentGameInfo)
+		}
+		for k, v := range m {
+			d.CurrentGames[r][k] = v
+		}
+	}
+	for r, m := range o.ThirdPartyCodes {
+		if d.ThirdPartyCodes == nil {
+			d.ThirdPartyCodes = make(map[region.Region]map[string]string)
+		}
+		if d.ThirdPartyCodes[r] == nil {
+			d.ThirdPartyCodes[r] = make(map[string]string)
+		}
+		for k, v := range m {
+			d.ThirdPartyCodes[r][k] = v
+		}
+	}
+}
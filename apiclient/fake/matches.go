@@ -0,0 +1,190 @@
+package fake
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/constants/region"
+)
+
+// recentMatches is the number of matches returned by GetRecentMatchlist.
+const recentMatches = 20
+
+// defaultMatchIDCount is the number of match IDs returned by
+// GetMatchIDsByPUUID when no count is given.
+const defaultMatchIDCount = 20
+
+func (c *Client) GetMatch(ctx context.Context, r region.Region, matchID int64) (*apiclient.Match, error) {
+	if err := c.call(ctx, "GetMatch"); err != nil {
+		return nil, err
+	}
+	for _, m := range c.d.Matches[r] {
+		if m.GameID == matchID {
+			return &m, nil
+		}
+	}
+	return nil, notFound(string(r), "/lol/match/v4/matches")
+}
+
+func (c *Client) GetMatchTimeline(ctx context.Context, r region.Region, matchID int64) (*apiclient.MatchTimeline, error) {
+	if err := c.call(ctx, "GetMatchTimeline"); err != nil {
+		return nil, err
+	}
+	timeline, ok := c.d.Timelines[r][matchID]
+	if !ok {
+		return nil, notFound(string(r), "/lol/match/v4/timelines/by-match")
+	}
+	return &timeline, nil
+}
+
+// matchlist returns the full matchlist for the account, sorted by timestamp
+// descending, or false if the account has not played any matches.
+func (c *Client) matchlist(r region.Region, accountID string) (*apiclient.Matchlist, bool) {
+	var res apiclient.Matchlist
+	for _, m := range c.d.Matches[r] {
+		for _, id := range m.ParticipantIdentities {
+			if id.Player.AccountID != accountID && id.Player.CurrentAccountID != accountID {
+				continue
+			}
+			ref := apiclient.MatchReference{
+				GameID:     m.GameID,
+				PlatformID: m.PlatformID,
+				Season:     m.SeasonID,
+				Queue:      m.QueueID,
+				Timestamp:  m.GameCreation,
+			}
+			for _, p := range m.Participants {
+				if p.ParticipantID == id.ParticipantID {
+					ref.Champion = p.ChampionID
+					ref.Lane = p.Timeline.Lane
+					ref.Role = p.Timeline.Role
+				}
+			}
+			res.Matches = append(res.Matches, ref)
+			break
+		}
+	}
+	if len(res.Matches) == 0 {
+		return nil, false
+	}
+	sort.SliceStable(res.Matches, func(i, j int) bool {
+		return res.Matches[i].Timestamp > res.Matches[j].Timestamp
+	})
+	res.TotalGames = len(res.Matches)
+	res.EndIndex = len(res.Matches)
+	return &res, true
+}
+
+// GetMatchlist returns the matches in the dataset that the account played,
+// filtered in the same way as apiclient.FilterMatchlist.
+func (c *Client) GetMatchlist(ctx context.Context, r region.Region, accountID string, opts *apiclient.GetMatchlistOptions) (*apiclient.Matchlist, error) {
+	if err := c.call(ctx, "GetMatchlist"); err != nil {
+		return nil, err
+	}
+	ml, ok := c.matchlist(r, accountID)
+	if !ok {
+		return nil, notFound(string(r), "/lol/match/v4/matchlists/by-account")
+	}
+	return apiclient.FilterMatchlist(ml, opts), nil
+}
+
+func (c *Client) GetRecentMatchlist(ctx context.Context, r region.Region, accountID string) (*apiclient.Matchlist, error) {
+	if err := c.call(ctx, "GetRecentMatchlist"); err != nil {
+		return nil, err
+	}
+	ml, ok := c.matchlist(r, accountID)
+	if !ok {
+		return nil, notFound(string(r), "/lol/match/v4/matchlists/by-account")
+	}
+	if len(ml.Matches) > recentMatches {
+		ml.Matches = ml.Matches[:recentMatches]
+	}
+	ml.TotalGames = len(ml.Matches)
+	ml.EndIndex = len(ml.Matches)
+	return ml, nil
+}
+
+// sameRouting returns true if the platform with the given ID is served by the
+// same regional cluster as the given platform.
+func sameRouting(platformID string, r region.Region) bool {
+	for _, p := range region.All() {
+		if strings.EqualFold(string(p), platformID) {
+			return p.Routing() == r.Routing()
+		}
+	}
+	return false
+}
+
+// GetMatchIDsByPUUID returns the IDs of matches in the dataset that the PUUID
+// played, most recent first. The match type filter is not supported and is
+// ignored, since match-v5 data does not record it.
+func (c *Client) GetMatchIDsByPUUID(ctx context.Context, r region.Region, puuid string, opts *apiclient.GetMatchIDsOptions) ([]string, error) {
+	if err := c.call(ctx, "GetMatchIDsByPUUID"); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &apiclient.GetMatchIDsOptions{}
+	}
+
+	var matches []*apiclient.MatchV5
+	for i := range c.d.MatchesV5 {
+		m := &c.d.MatchesV5[i]
+		if !sameRouting(m.Info.PlatformID, r) || m.Participant(puuid) == nil {
+			continue
+		}
+		if opts.Queue != nil && m.Info.QueueID != *opts.Queue {
+			continue
+		}
+		started := m.Info.GameStartTimestamp.Time()
+		if opts.StartTime != nil && started.Before(*opts.StartTime) {
+			continue
+		}
+		if opts.EndTime != nil && started.After(*opts.EndTime) {
+			continue
+		}
+		matches = append(matches, m)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Info.GameStartTimestamp > matches[j].Info.GameStartTimestamp
+	})
+
+	start, count := 0, defaultMatchIDCount
+	if opts.Start != nil {
+		start = *opts.Start
+	}
+	if opts.Count != nil {
+		count = *opts.Count
+	}
+	// Unlike match-v4, an empty page is not an error.
+	ids := []string{}
+	for i := start; i < len(matches) && i < start+count; i++ {
+		ids = append(ids, matches[i].Metadata.MatchID)
+	}
+	return ids, nil
+}
+
+func (c *Client) GetMatchV5(ctx context.Context, r region.Region, matchID string) (*apiclient.MatchV5, error) {
+	if err := c.call(ctx, "GetMatchV5"); err != nil {
+		return nil, err
+	}
+	for _, m := range c.d.MatchesV5 {
+		if m.Metadata.MatchID == matchID {
+			return &m, nil
+		}
+	}
+	return nil, notFound(string(r.Routing()), "/lol/match/v5/matches")
+}
+
+func (c *Client) GetMatchTimelineV5(ctx context.Context, r region.Region, matchID string) (*apiclient.MatchTimelineV5, error) {
+	if err := c.call(ctx, "GetMatchTimelineV5"); err != nil {
+		return nil, err
+	}
+	for _, t := range c.d.TimelinesV5 {
+		if t.Metadata.MatchID == matchID {
+			return &t, nil
+		}
+	}
+	return nil, notFound(string(r.Routing()), "/lol/match/v5/matches")
+}
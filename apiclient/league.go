@@ -3,6 +3,9 @@ package apiclient
 import (
 	"context"
 	"fmt"
+	"net/url"
+
+	"github.com/yuhanfang/riot/constants/division"
 	"github.com/yuhanfang/riot/constants/queue"
 	"github.com/yuhanfang/riot/constants/region"
 	"github.com/yuhanfang/riot/constants/tier"
@@ -40,6 +43,7 @@ type MiniSeries struct {
 type LeaguePosition struct {
 	QueueType    string     `json:"queueType",datastore:",noindex"`
 	SummonerName string     `json:"summonerName",datastore:",noindex"`
+	SummonerID   string     `json:"summonerId",datastore:",noindex"`
 	HotStreak    bool       `json:"hotStreak",datastore:",noindex"`
 	MiniSeries   MiniSeries `json:"miniSeries",datastore:",noindex"`
 	Wins         int        `json:"wins",datastore:",noindex"`
@@ -84,3 +88,76 @@ func (c *client) GetAllLeaguePositionsForSummoner(ctx context.Context, r region.
 	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/league/v4/entries/by-summoner", fmt.Sprintf("/%s", summonerID), nil, &res)
 	return res, err
 }
+
+func (c *client) GetLeagueEntries(ctx context.Context, r region.Region, q queue.Queue, t tier.Tier, d division.Division, page int) ([]LeaguePosition, error) {
+	var res []LeaguePosition
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/league/v4/entries", fmt.Sprintf("/%s/%s/%s", q.String(), t, d), pageValues(page), &res)
+	return res, err
+}
+
+func (c *client) GetLeagueExpEntries(ctx context.Context, r region.Region, q queue.Queue, t tier.Tier, d division.Division, page int) ([]LeaguePosition, error) {
+	var res []LeaguePosition
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/league-exp/v4/entries", fmt.Sprintf("/%s/%s/%s", q.String(), t, d), pageValues(page), &res)
+	return res, err
+}
+
+// pageValues returns the query for the given page, which starts at 1.
+func pageValues(page int) url.Values {
+	if page < 1 {
+		page = 1
+	}
+	return url.Values{"page": []string{fmt.Sprintf("%d", page)}}
+}
+
+// LeagueEntriesIterator walks all pages of league entries in a queue, tier
+// and division. It is illegal to construct an instance directly. Use
+// NewLeagueEntriesIterator or NewLeagueExpEntriesIterator to return a valid
+// instance. The LeagueEntriesIterator is not threadsafe.
+type LeagueEntriesIterator struct {
+	get func(ctx context.Context, r region.Region, q queue.Queue, t tier.Tier, d division.Division, page int) ([]LeaguePosition, error)
+	r   region.Region
+	q   queue.Queue
+	t   tier.Tier
+	d   division.Division
+
+	// page is the next page to fetch.
+	page    int
+	entries []LeaguePosition
+	done    bool
+}
+
+// NewLeagueEntriesIterator returns an iterator over league entries from
+// GetLeagueEntries.
+func NewLeagueEntriesIterator(c Client, r region.Region, q queue.Queue, t tier.Tier, d division.Division) *LeagueEntriesIterator {
+	return &LeagueEntriesIterator{get: c.GetLeagueEntries, r: r, q: q, t: t, d: d, page: 1}
+}
+
+// NewLeagueExpEntriesIterator returns an iterator over league entries from
+// GetLeagueExpEntries.
+func NewLeagueExpEntriesIterator(c Client, r region.Region, q queue.Queue, t tier.Tier, d division.Division) *LeagueEntriesIterator {
+	return &LeagueEntriesIterator{get: c.GetLeagueExpEntries, r: r, q: q, t: t, d: d, page: 1}
+}
+
+// Next returns the next league entry, or ErrIteratorDone if there are no more
+// entries. If Next returns any other error, such as a context error, it may be
+// called again to retry.
+func (it *LeagueEntriesIterator) Next(ctx context.Context) (*LeaguePosition, error) {
+	for len(it.entries) == 0 {
+		if it.done {
+			return nil, ErrIteratorDone
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		entries, err := it.get(ctx, it.r, it.q, it.t, it.d, it.page)
+		if err != nil {
+			return nil, err
+		}
+		it.page++
+		it.entries = entries
+		it.done = len(entries) == 0
+	}
+	e := it.entries[0]
+	it.entries = it.entries[1:]
+	return &e, nil
+}
@@ -0,0 +1,99 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/yuhanfang/riot/constants/division"
+	"github.com/yuhanfang/riot/constants/queue"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/constants/tier"
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/testing/doertest"
+)
+
+// leaguePages returns a Doer that serves the given pages of league entries in
+// order, followed by empty pages, and records the URI of every request.
+func leaguePages(uris *[]string, pages ...string) doertest.DoerFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		*uris = append(*uris, req.URL.RequestURI())
+		body := "[]"
+		if len(pages) > 0 {
+			body, pages = pages[0], pages[1:]
+		}
+		return doertest.Response(req, http.StatusOK, nil, body), nil
+	}
+}
+
+func TestGetLeagueEntries(t *testing.T) {
+	var uris []string
+	c := New("key", leaguePages(&uris, `[{"summonerId": "a"}]`, `[{"summonerId": "b"}]`), ratelimit.NewLimiter())
+	ctx := context.Background()
+
+	entries, err := c.GetLeagueEntries(ctx, region.NA1, queue.RankedSolo5x5, tier.Diamond, division.II, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].SummonerID != "a" {
+		t.Errorf("got entries %+v, want summoner a", entries)
+	}
+	if _, err := c.GetLeagueExpEntries(ctx, region.NA1, queue.RankedFlexSR, tier.Master, division.I, 3); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"/lol/league/v4/entries/RANKED_SOLO_5x5/DIAMOND/II?page=1",
+		"/lol/league-exp/v4/entries/RANKED_FLEX_SR/MASTER/I?page=3",
+	}
+	if !reflect.DeepEqual(uris, want) {
+		t.Errorf("got URIs %v, want %v", uris, want)
+	}
+}
+
+func TestLeagueEntriesIterator(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		new  func(Client, region.Region, queue.Queue, tier.Tier, division.Division) *LeagueEntriesIterator
+		path string
+	}{
+		{"entries", NewLeagueEntriesIterator, "/lol/league/v4/entries"},
+		{"exp entries", NewLeagueExpEntriesIterator, "/lol/league-exp/v4/entries"},
+	} {
+		var uris []string
+		doer := leaguePages(&uris, `[{"summonerId": "a"}, {"summonerId": "b"}]`, `[{"summonerId": "c"}]`)
+		c := New("key", doer, ratelimit.NewLimiter())
+		it := test.new(c, region.NA1, queue.RankedSolo5x5, tier.Gold, division.IV)
+
+		var ids []string
+		for {
+			e, err := it.Next(context.Background())
+			if err == ErrIteratorDone {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids = append(ids, e.SummonerID)
+		}
+		if want := []string{"a", "b", "c"}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("%s: got summoners %v, want %v", test.name, ids, want)
+		}
+
+		// Paging stops at the first empty page.
+		var want []string
+		for page := 1; page <= 3; page++ {
+			want = append(want, fmt.Sprintf("%s/RANKED_SOLO_5x5/GOLD/IV?page=%d", test.path, page))
+		}
+		if !reflect.DeepEqual(uris, want) {
+			t.Errorf("%s: got URIs %v, want %v", test.name, uris, want)
+		}
+		if _, err := it.Next(context.Background()); err != ErrIteratorDone {
+			t.Errorf("%s: got %v after the last page, want ErrIteratorDone", test.name, err)
+		}
+		if len(uris) != 3 {
+			t.Errorf("%s: made %d requests after the last page, want none", test.name, len(uris)-3)
+		}
+	}
+}
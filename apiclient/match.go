@@ -252,6 +252,20 @@ func (i *IntervalValues) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalJSON encodes the values in the same format as the API, so that they
+// can be unmarshaled again.
+func (i IntervalValues) MarshalJSON() ([]byte, error) {
+	obj := make(map[string]float64)
+	for _, v := range i {
+		end := strconv.Itoa(v.Interval.End)
+		if v.Interval.End == 999 {
+			end = "end"
+		}
+		obj[fmt.Sprintf("%d-%s", v.Interval.Begin, end)] = v.Value
+	}
+	return json.Marshal(obj)
+}
+
 type IntervalValue struct {
 	Interval Interval `json:"interval"`
 	Value    float64  `json:"value"`
@@ -355,6 +369,89 @@ func (c *client) GetMatchlist(ctx context.Context, r region.Region, accountID st
 	return &res, err
 }
 
+// FilterMatchlist applies the given options to the matchlist, returning a new
+// match list that is filtered. It is used by clients that serve matchlists
+// without calling the API, and filters in the same way as the API. The given
+// options are not modified.
+//
+// As in the API, BeginIndex and EndIndex index the matches that pass the other
+// filters, so that pages of a filtered matchlist do not skip matches. Earlier
+// versions indexed the unfiltered matchlist instead.
+func FilterMatchlist(m *Matchlist, opts *GetMatchlistOptions) *Matchlist {
+	if opts == nil {
+		return m
+	}
+	var res Matchlist
+
+	queues := make(map[queue.Queue]bool)
+	for _, q := range opts.Queue {
+		queues[q] = true
+	}
+	seasons := make(map[season.Season]bool)
+	for _, s := range opts.Season {
+		seasons[s] = true
+	}
+	champions := make(map[champion.Champion]bool)
+	for _, c := range opts.Champion {
+		champions[c] = true
+	}
+
+	beginIndex, endIndex := opts.BeginIndex, opts.EndIndex
+	if beginIndex == nil && endIndex != nil {
+		begin := 0
+		beginIndex = &begin
+	} else if beginIndex != nil && endIndex == nil {
+		end := *beginIndex + 100
+		endIndex = &end
+	}
+
+	var i int
+	for _, match := range m.Matches {
+		if len(opts.Queue) > 0 && !queues[match.Queue] {
+			continue
+		}
+		if len(opts.Season) > 0 && !seasons[match.Season] {
+			continue
+		}
+		if len(opts.Champion) > 0 && !champions[match.Champion] {
+			continue
+		}
+
+		ts := match.Timestamp.Time()
+		if opts.BeginTime != nil && opts.EndTime != nil {
+			if opts.BeginTime.After(ts) || opts.EndTime.Before(ts) {
+				continue
+			}
+		} else if opts.EndTime != nil {
+			if opts.EndTime.Before(ts) {
+				continue
+			}
+		} else if opts.BeginTime != nil {
+			if opts.BeginTime.After(ts) {
+				continue
+			}
+		}
+
+		index := i
+		i++
+		if beginIndex != nil && endIndex != nil {
+			if index < *beginIndex || index >= *endIndex {
+				continue
+			}
+		}
+		res.Matches = append(res.Matches, match)
+	}
+
+	res.TotalGames = len(res.Matches)
+	if beginIndex != nil {
+		res.StartIndex = *beginIndex
+	}
+	if endIndex != nil {
+		res.EndIndex = *endIndex
+	}
+	return &res
+}
+
 func (c *client) GetRecentMatchlist(ctx context.Context, r region.Region, accountID string) (*Matchlist, error) {
 	var res Matchlist
 	// Recent matchlists are a separate API call from matchlists, even though
@@ -389,6 +486,16 @@ func (p *ParticipantFrames) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// MarshalJSON encodes the frames in the same format as the API, so that they
+// can be unmarshaled again.
+func (p ParticipantFrames) MarshalJSON() ([]byte, error) {
+	obj := make(map[int]MatchParticipantFrame)
+	for _, v := range p.Frames {
+		obj[v.ParticipantID] = v
+	}
+	return json.Marshal(obj)
+}
+
 type MatchFrame struct {
 	Timestamp         types.Milliseconds `json:"timestamp"`
 	ParticipantFrames ParticipantFrames  `json:"participantFrames"`
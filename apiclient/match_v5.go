@@ -0,0 +1,471 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/champion"
+	"github.com/yuhanfang/riot/constants/event"
+	"github.com/yuhanfang/riot/constants/lane"
+	"github.com/yuhanfang/riot/constants/matchtype"
+	"github.com/yuhanfang/riot/constants/queue"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/types"
+)
+
+// MatchV5 is a match returned by the match-v5 API. Matches are identified by
+// string IDs such as "NA1_123", and participants are identified by PUUID.
+type MatchV5 struct {
+	Metadata MatchMetadata `json:"metadata"`
+	Info     MatchInfo     `json:"info"`
+}
+
+// Participant returns the participant with the given PUUID, or nil if the
+// PUUID did not play in the match.
+func (m *MatchV5) Participant(puuid string) *MatchParticipant {
+	for i := range m.Info.Participants {
+		if m.Info.Participants[i].PUUID == puuid {
+			return &m.Info.Participants[i]
+		}
+	}
+	return nil
+}
+
+type MatchMetadata struct {
+	DataVersion  string   `json:"dataVersion"`  // Match data version.
+	MatchID      string   `json:"matchId"`      // Match ID, for example "NA1_123".
+	Participants []string `json:"participants"` // PUUIDs of the participants.
+}
+
+type MatchInfo struct {
+	EndOfGameResult    string             `json:"endOfGameResult"`
+	GameCreation       types.Milliseconds `json:"gameCreation"`       // Unix milliseconds when the game was created on the game server.
+	GameDuration       int64              `json:"gameDuration"`       // Game length in seconds if GameEndTimestamp is set, otherwise in milliseconds.
+	GameEndTimestamp   types.Milliseconds `json:"gameEndTimestamp"`   // Unix milliseconds when the game ended on the game server.
+	GameID             int64              `json:"gameId"`             // Numeric game ID on the platform.
+	GameMode           string             `json:"gameMode"`           // GameMode
+	GameName           string             `json:"gameName"`           // GameName
+	GameStartTimestamp types.Milliseconds `json:"gameStartTimestamp"` // Unix milliseconds when the match started on the game server.
+	GameType           string             `json:"gameType"`           // GameType
+	GameVersion        string             `json:"gameVersion"`        // Version that the game was played on.
+	MapID              int                `json:"mapId"`              // MapID is a constant of the map played on.
+	Participants       []MatchParticipant `json:"participants"`       // Participants
+	PlatformID         string             `json:"platformId"`         // Platform where the match was played.
+	QueueID            queue.Queue        `json:"queueId"`            // QueueID is a constant that refers to the queue.
+	Teams              []MatchTeam        `json:"teams"`              // Teams
+	TournamentCode     string             `json:"tournamentCode"`     // Tournament code used to generate the match, if any.
+}
+
+// Duration returns the game length, accounting for the change of units of
+// GameDuration in patch 11.20.
+func (m MatchInfo) Duration() time.Duration {
+	if m.GameEndTimestamp == 0 {
+		return types.Milliseconds(m.GameDuration).Duration()
+	}
+	return time.Duration(m.GameDuration) * time.Second
+}
+
+type MatchParticipant struct {
+	AllInPings                     int               `json:"allInPings"`
+	AssistMePings                  int               `json:"assistMePings"`
+	Assists                        int               `json:"assists"`
+	BaronKills                     int               `json:"baronKills"`
+	BountyLevel                    int               `json:"bountyLevel"`
+	ChampExperience                int               `json:"champExperience"`
+	ChampLevel                     int               `json:"champLevel"`
+	ChampionID                     champion.Champion `json:"championId"`
+	ChampionName                   string            `json:"championName"`
+	ChampionTransform              int               `json:"championTransform"` // Kayn transformation: 0 for none, 1 for Slayer, 2 for Assassin.
+	ConsumablesPurchased           int               `json:"consumablesPurchased"`
+	DamageDealtToBuildings         int64             `json:"damageDealtToBuildings"`
+	DamageDealtToObjectives        int64             `json:"damageDealtToObjectives"`
+	DamageDealtToTurrets           int64             `json:"damageDealtToTurrets"`
+	DamageSelfMitigated            int64             `json:"damageSelfMitigated"`
+	Deaths                         int               `json:"deaths"`
+	DetectorWardsPlaced            int               `json:"detectorWardsPlaced"`
+	DoubleKills                    int               `json:"doubleKills"`
+	DragonKills                    int               `json:"dragonKills"`
+	FirstBloodAssist               bool              `json:"firstBloodAssist"`
+	FirstBloodKill                 bool              `json:"firstBloodKill"`
+	FirstTowerAssist               bool              `json:"firstTowerAssist"`
+	FirstTowerKill                 bool              `json:"firstTowerKill"`
+	GameEndedInEarlySurrender      bool              `json:"gameEndedInEarlySurrender"`
+	GameEndedInSurrender           bool              `json:"gameEndedInSurrender"`
+	GoldEarned                     int               `json:"goldEarned"`
+	GoldSpent                      int               `json:"goldSpent"`
+	IndividualPosition             string            `json:"individualPosition"` // Best guess for which position the player actually played in isolation of anything else.
+	InhibitorKills                 int               `json:"inhibitorKills"`
+	InhibitorTakedowns             int               `json:"inhibitorTakedowns"`
+	InhibitorsLost                 int               `json:"inhibitorsLost"`
+	Item0                          int               `json:"item0"`
+	Item1                          int               `json:"item1"`
+	Item2                          int               `json:"item2"`
+	Item3                          int               `json:"item3"`
+	Item4                          int               `json:"item4"`
+	Item5                          int               `json:"item5"`
+	Item6                          int               `json:"item6"`
+	ItemsPurchased                 int               `json:"itemsPurchased"`
+	KillingSprees                  int               `json:"killingSprees"`
+	Kills                          int               `json:"kills"`
+	Lane                           string            `json:"lane"`
+	LargestCriticalStrike          int               `json:"largestCriticalStrike"`
+	LargestKillingSpree            int               `json:"largestKillingSpree"`
+	LargestMultiKill               int               `json:"largestMultiKill"`
+	LongestTimeSpentLiving         int               `json:"longestTimeSpentLiving"`
+	MagicDamageDealt               int64             `json:"magicDamageDealt"`
+	MagicDamageDealtToChampions    int64             `json:"magicDamageDealtToChampions"`
+	MagicDamageTaken               int64             `json:"magicDamageTaken"`
+	NeutralMinionsKilled           int               `json:"neutralMinionsKilled"`
+	NexusKills                     int               `json:"nexusKills"`
+	NexusLost                      int               `json:"nexusLost"`
+	NexusTakedowns                 int               `json:"nexusTakedowns"`
+	ObjectivesStolen               int               `json:"objectivesStolen"`
+	ObjectivesStolenAssists        int               `json:"objectivesStolenAssists"`
+	ParticipantID                  int               `json:"participantId"`
+	PentaKills                     int               `json:"pentaKills"`
+	Perks                          MatchPerks        `json:"perks"`
+	PhysicalDamageDealt            int64             `json:"physicalDamageDealt"`
+	PhysicalDamageDealtToChampions int64             `json:"physicalDamageDealtToChampions"`
+	PhysicalDamageTaken            int64             `json:"physicalDamageTaken"`
+	ProfileIcon                    int               `json:"profileIcon"`
+	PUUID                          string            `json:"puuid"`
+	QuadraKills                    int               `json:"quadraKills"`
+	RiotIDGameName                 string            `json:"riotIdGameName"`
+	RiotIDTagline                  string            `json:"riotIdTagline"`
+	Role                           string            `json:"role"`
+	SightWardsBoughtInGame         int               `json:"sightWardsBoughtInGame"`
+	Spell1Casts                    int               `json:"spell1Casts"`
+	Spell2Casts                    int               `json:"spell2Casts"`
+	Spell3Casts                    int               `json:"spell3Casts"`
+	Spell4Casts                    int               `json:"spell4Casts"`
+	Summoner1Casts                 int               `json:"summoner1Casts"`
+	Summoner1ID                    int               `json:"summoner1Id"`
+	Summoner2Casts                 int               `json:"summoner2Casts"`
+	Summoner2ID                    int               `json:"summoner2Id"`
+	SummonerID                     string            `json:"summonerId"`
+	SummonerLevel                  int               `json:"summonerLevel"`
+	SummonerName                   string            `json:"summonerName"`
+	TeamEarlySurrendered           bool              `json:"teamEarlySurrendered"`
+	TeamID                         int               `json:"teamId"`
+	TeamPosition                   string            `json:"teamPosition"` // Best guess for which position the player actually played if we add the constraint that each team must have one top player, one jungle, one middle, etc.
+	TimeCCingOthers                int64             `json:"timeCCingOthers"`
+	TimePlayed                     int               `json:"timePlayed"`
+	TotalDamageDealt               int64             `json:"totalDamageDealt"`
+	TotalDamageDealtToChampions    int64             `json:"totalDamageDealtToChampions"`
+	TotalDamageShieldedOnTeammates int64             `json:"totalDamageShieldedOnTeammates"`
+	TotalDamageTaken               int64             `json:"totalDamageTaken"`
+	TotalHeal                      int64             `json:"totalHeal"`
+	TotalHealsOnTeammates          int64             `json:"totalHealsOnTeammates"`
+	TotalMinionsKilled             int               `json:"totalMinionsKilled"`
+	TotalTimeCCDealt               int               `json:"totalTimeCCDealt"`
+	TotalTimeSpentDead             int               `json:"totalTimeSpentDead"`
+	TotalUnitsHealed               int               `json:"totalUnitsHealed"`
+	TripleKills                    int               `json:"tripleKills"`
+	TrueDamageDealt                int64             `json:"trueDamageDealt"`
+	TrueDamageDealtToChampions     int64             `json:"trueDamageDealtToChampions"`
+	TrueDamageTaken                int64             `json:"trueDamageTaken"`
+	TurretKills                    int               `json:"turretKills"`
+	TurretTakedowns                int               `json:"turretTakedowns"`
+	TurretsLost                    int               `json:"turretsLost"`
+	UnrealKills                    int               `json:"unrealKills"`
+	VisionScore                    int64             `json:"visionScore"`
+	VisionWardsBoughtInGame        int               `json:"visionWardsBoughtInGame"`
+	WardsKilled                    int               `json:"wardsKilled"`
+	WardsPlaced                    int               `json:"wardsPlaced"`
+	Win                            bool              `json:"win"`
+}
+
+type MatchPerks struct {
+	StatPerks MatchPerkStats   `json:"statPerks"`
+	Styles    []MatchPerkStyle `json:"styles"`
+}
+
+type MatchPerkStats struct {
+	Defense int64 `json:"defense"`
+	Flex    int64 `json:"flex"`
+	Offense int64 `json:"offense"`
+}
+
+type MatchPerkStyle struct {
+	Description string                    `json:"description"` // Either "primaryStyle" or "subStyle".
+	Selections  []MatchPerkStyleSelection `json:"selections"`
+	Style       int64                     `json:"style"`
+}
+
+type MatchPerkStyleSelection struct {
+	Perk int64 `json:"perk"`
+	Var1 int   `json:"var1"`
+	Var2 int   `json:"var2"`
+	Var3 int   `json:"var3"`
+}
+
+type MatchTeam struct {
+	Bans       []MatchBan      `json:"bans"`
+	Objectives MatchObjectives `json:"objectives"`
+	TeamID     int             `json:"teamId"`
+	Win        bool            `json:"win"`
+}
+
+type MatchBan struct {
+	ChampionID champion.Champion `json:"championId"`
+	PickTurn   int               `json:"pickTurn"`
+}
+
+type MatchObjectives struct {
+	Baron      MatchObjective `json:"baron"`
+	Champion   MatchObjective `json:"champion"`
+	Dragon     MatchObjective `json:"dragon"`
+	Horde      MatchObjective `json:"horde"`
+	Inhibitor  MatchObjective `json:"inhibitor"`
+	RiftHerald MatchObjective `json:"riftHerald"`
+	Tower      MatchObjective `json:"tower"`
+}
+
+type MatchObjective struct {
+	First bool `json:"first"`
+	Kills int  `json:"kills"`
+}
+
+// GetMatchIDsOptions provides filtering options for GetMatchIDsByPUUID. The
+// zero value means that the option will not be used in filtering.
+type GetMatchIDsOptions struct {
+	Queue     *queue.Queue    `json:"queue"`
+	Type      *matchtype.Type `json:"type"`
+	StartTime *time.Time      `json:"startTime"`
+	EndTime   *time.Time      `json:"endTime"`
+	Start     *int            `json:"start"` // Start index, defaults to 0.
+	Count     *int            `json:"count"` // Number of match IDs to return, from 0 to 100. Defaults to 20.
+}
+
+func (c *client) GetMatchIDsByPUUID(ctx context.Context, r region.Region, puuid string, opts *GetMatchIDsOptions) ([]string, error) {
+	var (
+		res  []string
+		vals url.Values
+	)
+
+	if opts != nil {
+		vals = url.Values(make(map[string][]string))
+		if opts.Queue != nil {
+			vals.Add("queue", fmt.Sprintf("%d", *opts.Queue))
+		}
+		if opts.Type != nil {
+			vals.Add("type", string(*opts.Type))
+		}
+		// Unlike match-v4, times are given in epoch seconds.
+		if opts.StartTime != nil {
+			vals.Add("startTime", fmt.Sprintf("%d", opts.StartTime.Unix()))
+		}
+		if opts.EndTime != nil {
+			vals.Add("endTime", fmt.Sprintf("%d", opts.EndTime.Unix()))
+		}
+		if opts.Start != nil {
+			vals.Add("start", fmt.Sprintf("%d", *opts.Start))
+		}
+		if opts.Count != nil {
+			vals.Add("count", fmt.Sprintf("%d", *opts.Count))
+		}
+	}
+	_, err := c.dispatchAndUnmarshalRouting(ctx, r.Routing(), "/lol/match/v5/matches/by-puuid", fmt.Sprintf("/%s/ids", puuid), vals, "", &res)
+	return res, err
+}
+
+func (c *client) GetMatchV5(ctx context.Context, r region.Region, matchID string) (*MatchV5, error) {
+	var res MatchV5
+	_, err := c.dispatchAndUnmarshalRouting(ctx, r.Routing(), "/lol/match/v5/matches", fmt.Sprintf("/%s", matchID), nil, "", &res)
+	return &res, err
+}
+
+// MatchTimelineV5 is a match timeline returned by the match-v5 API.
+type MatchTimelineV5 struct {
+	Metadata MatchMetadata `json:"metadata"`
+	Info     TimelineInfo  `json:"info"`
+}
+
+// ParticipantID returns the in-game participant ID for the given PUUID, or
+// zero if the PUUID did not play in the match. Timeline frames and events
+// refer to participants by participant ID.
+func (m *MatchTimelineV5) ParticipantID(puuid string) int {
+	for _, p := range m.Info.Participants {
+		if p.PUUID == puuid {
+			return p.ParticipantID
+		}
+	}
+	return 0
+}
+
+type TimelineInfo struct {
+	EndOfGameResult string                `json:"endOfGameResult"`
+	FrameInterval   types.Milliseconds    `json:"frameInterval"`
+	Frames          []TimelineFrame       `json:"frames"`
+	GameID          int64                 `json:"gameId"`
+	Participants    []TimelineParticipant `json:"participants"`
+}
+
+type TimelineParticipant struct {
+	ParticipantID int    `json:"participantId"`
+	PUUID         string `json:"puuid"`
+}
+
+type TimelineFrame struct {
+	Events            []TimelineEvent           `json:"events"`
+	ParticipantFrames TimelineParticipantFrames `json:"participantFrames"`
+	Timestamp         types.Milliseconds        `json:"timestamp"`
+}
+
+// TimelineParticipantFrames stores frames corresponding to each participant,
+// sorted ascending by participant ID.
+type TimelineParticipantFrames struct {
+	Frames []TimelineParticipantFrame `json:"frames"`
+}
+
+func (p *TimelineParticipantFrames) UnmarshalJSON(b []byte) error {
+	var obj map[int]TimelineParticipantFrame
+	err := json.Unmarshal(b, &obj)
+	if err != nil {
+		return err
+	}
+	var vals []TimelineParticipantFrame
+	for _, v := range obj {
+		vals = append(vals, v)
+	}
+	sort.Slice(vals, func(i, j int) bool {
+		return vals[i].ParticipantID < vals[j].ParticipantID
+	})
+	p.Frames = vals
+	return nil
+}
+
+// MarshalJSON encodes the frames in the same format as the API, so that they
+// can be unmarshaled again.
+func (p TimelineParticipantFrames) MarshalJSON() ([]byte, error) {
+	obj := make(map[int]TimelineParticipantFrame)
+	for _, v := range p.Frames {
+		obj[v.ParticipantID] = v
+	}
+	return json.Marshal(obj)
+}
+
+type TimelineParticipantFrame struct {
+	ChampionStats            TimelineChampionStats `json:"championStats"`
+	CurrentGold              int                   `json:"currentGold"`
+	DamageStats              TimelineDamageStats   `json:"damageStats"`
+	GoldPerSecond            int                   `json:"goldPerSecond"`
+	JungleMinionsKilled      int                   `json:"jungleMinionsKilled"`
+	Level                    int                   `json:"level"`
+	MinionsKilled            int                   `json:"minionsKilled"`
+	ParticipantID            int                   `json:"participantId"`
+	Position                 MatchPosition         `json:"position"`
+	TimeEnemySpentControlled int64                 `json:"timeEnemySpentControlled"`
+	TotalGold                int                   `json:"totalGold"`
+	XP                       int                   `json:"xp"`
+}
+
+type TimelineChampionStats struct {
+	AbilityHaste         int `json:"abilityHaste"`
+	AbilityPower         int `json:"abilityPower"`
+	Armor                int `json:"armor"`
+	ArmorPen             int `json:"armorPen"`
+	ArmorPenPercent      int `json:"armorPenPercent"`
+	AttackDamage         int `json:"attackDamage"`
+	AttackSpeed          int `json:"attackSpeed"`
+	BonusArmorPenPercent int `json:"bonusArmorPenPercent"`
+	BonusMagicPenPercent int `json:"bonusMagicPenPercent"`
+	CCReduction          int `json:"ccReduction"`
+	CooldownReduction    int `json:"cooldownReduction"`
+	Health               int `json:"health"`
+	HealthMax            int `json:"healthMax"`
+	HealthRegen          int `json:"healthRegen"`
+	Lifesteal            int `json:"lifesteal"`
+	MagicPen             int `json:"magicPen"`
+	MagicPenPercent      int `json:"magicPenPercent"`
+	MagicResist          int `json:"magicResist"`
+	MovementSpeed        int `json:"movementSpeed"`
+	Omnivamp             int `json:"omnivamp"`
+	PhysicalVamp         int `json:"physicalVamp"`
+	Power                int `json:"power"`
+	PowerMax             int `json:"powerMax"`
+	PowerRegen           int `json:"powerRegen"`
+	SpellVamp            int `json:"spellVamp"`
+}
+
+type TimelineDamageStats struct {
+	MagicDamageDone               int64 `json:"magicDamageDone"`
+	MagicDamageDoneToChampions    int64 `json:"magicDamageDoneToChampions"`
+	MagicDamageTaken              int64 `json:"magicDamageTaken"`
+	PhysicalDamageDone            int64 `json:"physicalDamageDone"`
+	PhysicalDamageDoneToChampions int64 `json:"physicalDamageDoneToChampions"`
+	PhysicalDamageTaken           int64 `json:"physicalDamageTaken"`
+	TotalDamageDone               int64 `json:"totalDamageDone"`
+	TotalDamageDoneToChampions    int64 `json:"totalDamageDoneToChampions"`
+	TotalDamageTaken              int64 `json:"totalDamageTaken"`
+	TrueDamageDone                int64 `json:"trueDamageDone"`
+	TrueDamageDoneToChampions     int64 `json:"trueDamageDoneToChampions"`
+	TrueDamageTaken               int64 `json:"trueDamageTaken"`
+}
+
+// TimelineEvent is a single timeline event. Which fields are populated
+// depends on the event Type.
+type TimelineEvent struct {
+	ActualStartTime         types.Milliseconds     `json:"actualStartTime"`
+	AfterID                 int                    `json:"afterId"`
+	AssistingParticipantIDs []int                  `json:"assistingParticipantIds"`
+	BeforeID                int                    `json:"beforeId"`
+	Bounty                  int                    `json:"bounty"`
+	BuildingType            string                 `json:"buildingType"`
+	CreatorID               int                    `json:"creatorId"`
+	GameID                  int64                  `json:"gameId"`
+	GoldGain                int                    `json:"goldGain"`
+	ItemID                  int                    `json:"itemId"`
+	KillerID                int                    `json:"killerId"`
+	KillerTeamID            int                    `json:"killerTeamId"`
+	KillStreakLength        int                    `json:"killStreakLength"`
+	KillType                string                 `json:"killType"`
+	LaneType                lane.Type              `json:"laneType"`
+	Level                   int                    `json:"level"`
+	LevelUpType             string                 `json:"levelUpType"`
+	MonsterSubType          string                 `json:"monsterSubType"`
+	MonsterType             string                 `json:"monsterType"`
+	MultiKillLength         int                    `json:"multiKillLength"`
+	Name                    string                 `json:"name"`
+	ParticipantID           int                    `json:"participantId"`
+	Position                MatchPosition          `json:"position"`
+	RealTimestamp           types.Milliseconds     `json:"realTimestamp"`
+	ShutdownBounty          int                    `json:"shutdownBounty"`
+	SkillSlot               int                    `json:"skillSlot"`
+	TeamID                  int                    `json:"teamId"`
+	Timestamp               types.Milliseconds     `json:"timestamp"`
+	TowerType               string                 `json:"towerType"`
+	TransformType           string                 `json:"transformType"`
+	Type                    event.Event            `json:"type"`
+	VictimDamageDealt       []TimelineDamageDetail `json:"victimDamageDealt"`
+	VictimDamageReceived    []TimelineDamageDetail `json:"victimDamageReceived"`
+	VictimID                int                    `json:"victimId"`
+	WardType                string                 `json:"wardType"`
+	WinningTeam             int                    `json:"winningTeam"`
+}
+
+// TimelineDamageDetail describes one source of damage in a champion kill
+// recap.
+type TimelineDamageDetail struct {
+	Basic          bool   `json:"basic"`
+	MagicDamage    int64  `json:"magicDamage"`
+	Name           string `json:"name"`
+	ParticipantID  int    `json:"participantId"`
+	PhysicalDamage int64  `json:"physicalDamage"`
+	SpellName      string `json:"spellName"`
+	SpellSlot      int    `json:"spellSlot"`
+	TrueDamage     int64  `json:"trueDamage"`
+	Type           string `json:"type"`
+}
+
+func (c *client) GetMatchTimelineV5(ctx context.Context, r region.Region, matchID string) (*MatchTimelineV5, error) {
+	var res MatchTimelineV5
+	// Timelines share the match method prefix, so add "timeline" as a
+	// uniquifier for this method.
+	_, err := c.dispatchAndUnmarshalRouting(ctx, r.Routing(), "/lol/match/v5/matches", fmt.Sprintf("/%s/timeline", matchID), nil, "timeline", &res)
+	return &res, err
+}
@@ -0,0 +1,142 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/region"
+)
+
+const (
+	// maxMatchlistPage is the largest index range of a matchlist request.
+	maxMatchlistPage = 100
+
+	// maxMatchlistWindow is the largest time range of a matchlist request
+	// that specifies both a begin and end time.
+	maxMatchlistWindow = 7 * 24 * time.Hour
+)
+
+// MatchlistIterator walks an account's full match history, most recent match
+// first, by calling GetMatchlist as many times as needed. It is illegal to
+// construct an instance directly. Use NewMatchlistIterator to return a valid
+// instance. The MatchlistIterator is not threadsafe.
+type MatchlistIterator struct {
+	c         Client
+	r         region.Region
+	accountID string
+	opts      GetMatchlistOptions
+
+	// windowed is true if the time range is split into windows no longer than
+	// maxMatchlistWindow, starting with the most recent window.
+	windowed    bool
+	windowBegin time.Time
+	windowEnd   time.Time
+
+	// beginIndex is the index of the next page in the current window.
+	beginIndex int
+	page       []MatchReference
+	done       bool
+}
+
+// NewMatchlistIterator returns an iterator over the account's matches that
+// satisfy the options. The iterator manages BeginIndex and EndIndex itself, so
+// those options are ignored. BeginTime and EndTime may be arbitrarily far
+// apart; the iterator splits the range into windows that the API accepts.
+func NewMatchlistIterator(c Client, r region.Region, accountID string, opts *GetMatchlistOptions) *MatchlistIterator {
+	it := &MatchlistIterator{
+		c:         c,
+		r:         r,
+		accountID: accountID,
+	}
+	if opts != nil {
+		it.opts = *opts
+	}
+	it.opts.BeginIndex = nil
+	it.opts.EndIndex = nil
+	if it.opts.BeginTime != nil && it.opts.EndTime != nil {
+		it.windowed = true
+		it.windowEnd = *it.opts.EndTime
+		it.setWindowBegin()
+		it.done = it.opts.BeginTime.After(*it.opts.EndTime)
+	}
+	return it
+}
+
+// setWindowBegin starts the current window no more than maxMatchlistWindow
+// before it ends, and not before the requested begin time.
+func (it *MatchlistIterator) setWindowBegin() {
+	it.windowBegin = it.windowEnd.Add(-maxMatchlistWindow)
+	if it.windowBegin.Before(*it.opts.BeginTime) {
+		it.windowBegin = *it.opts.BeginTime
+	}
+}
+
+// Next returns the next match, or ErrIteratorDone if there are no more
+// matches. If Next returns any other error, such as a context error, it may be
+// called again to retry.
+func (it *MatchlistIterator) Next(ctx context.Context) (*MatchReference, error) {
+	for len(it.page) == 0 {
+		if it.done {
+			return nil, ErrIteratorDone
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := it.fetch(ctx); err != nil {
+			return nil, err
+		}
+	}
+	m := it.page[0]
+	it.page = it.page[1:]
+	return &m, nil
+}
+
+// fetch retrieves the next page, and advances to the next window or marks the
+// iterator done when the page is empty.
+func (it *MatchlistIterator) fetch(ctx context.Context) error {
+	var (
+		opts       = it.opts
+		beginIndex = it.beginIndex
+		endIndex   = it.beginIndex + maxMatchlistPage
+	)
+	opts.BeginIndex = &beginIndex
+	opts.EndIndex = &endIndex
+	if it.windowed {
+		opts.BeginTime = &it.windowBegin
+		opts.EndTime = &it.windowEnd
+	} else if opts.EndTime != nil {
+		// The API does not accept an end time without a begin time, so filter
+		// by end time locally.
+		opts.EndTime = nil
+	}
+
+	var matches []MatchReference
+	ml, err := it.c.GetMatchlist(ctx, it.r, it.accountID, &opts)
+	if err == nil {
+		matches = ml.Matches
+	} else if !errors.Is(err, ErrDataNotFound) {
+		// The API reports an empty page as not found.
+		return err
+	}
+
+	if len(matches) == 0 {
+		if it.windowed && it.windowBegin.After(*it.opts.BeginTime) {
+			it.windowEnd = it.windowBegin.Add(-time.Millisecond)
+			it.setWindowBegin()
+			it.beginIndex = 0
+			it.done = it.windowBegin.After(it.windowEnd)
+			return nil
+		}
+		it.done = true
+		return nil
+	}
+	it.beginIndex += len(matches)
+	for _, m := range matches {
+		if !it.windowed && it.opts.EndTime != nil && m.Timestamp.Time().After(*it.opts.EndTime) {
+			continue
+		}
+		it.page = append(it.page, m)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package apiclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/types"
+)
+
+// matchlistClient serves GetMatchlist from a full matchlist and records the
+// options of every call.
+type matchlistClient struct {
+	Client
+	matches []MatchReference
+	calls   []GetMatchlistOptions
+}
+
+func (c *matchlistClient) GetMatchlist(ctx context.Context, r region.Region, accountID string, opts *GetMatchlistOptions) (*Matchlist, error) {
+	c.calls = append(c.calls, *opts)
+	if *opts.EndIndex-*opts.BeginIndex > maxMatchlistPage {
+		panic("index range too large")
+	}
+	if opts.BeginTime != nil && opts.EndTime != nil && opts.EndTime.Sub(*opts.BeginTime) > maxMatchlistWindow {
+		panic("time range too large")
+	}
+	ml := FilterMatchlist(&Matchlist{Matches: c.matches}, opts)
+	if len(ml.Matches) == 0 {
+		return nil, &APIError{StatusCode: 404}
+	}
+	return ml, nil
+}
+
+func testMatchlistClient(n int, start time.Time, interval time.Duration) *matchlistClient {
+	c := &matchlistClient{}
+	for i := 0; i < n; i++ {
+		ts := start.Add(-time.Duration(i) * interval)
+		c.matches = append(c.matches, MatchReference{
+			GameID:    int64(n - i),
+			Timestamp: types.Milliseconds(ts.UnixNano() / int64(time.Millisecond)),
+		})
+	}
+	return c
+}
+
+func drain(t *testing.T, it *MatchlistIterator) []int64 {
+	var ids []int64
+	for {
+		m, err := it.Next(context.Background())
+		if err == ErrIteratorDone {
+			return ids
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, m.GameID)
+	}
+}
+
+func TestMatchlistIteratorPages(t *testing.T) {
+	c := testMatchlistClient(250, time.Now(), time.Hour)
+	ids := drain(t, NewMatchlistIterator(c, region.NA1, "account", nil))
+	if len(ids) != 250 || ids[0] != 250 || ids[249] != 1 {
+		t.Errorf("got %d matches from %v to %v, want 250 from 250 to 1", len(ids), ids[0], ids[len(ids)-1])
+	}
+	// Three full or partial pages, and an empty page.
+	if len(c.calls) != 4 {
+		t.Errorf("got %d calls, want 4", len(c.calls))
+	}
+}
+
+func TestMatchlistIteratorWindows(t *testing.T) {
+	end := time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC)
+	c := testMatchlistClient(100, end, 12*time.Hour)
+	begin := end.Add(-20 * 24 * time.Hour)
+	ids := drain(t, NewMatchlistIterator(c, region.NA1, "account", &GetMatchlistOptions{
+		BeginTime: &begin,
+		EndTime:   &end,
+	}))
+	// Twenty days of matches every twelve hours, inclusive.
+	if len(ids) != 41 || ids[0] != 100 || ids[40] != 60 {
+		t.Errorf("got %d matches from %v to %v, want 41 from 100 to 60", len(ids), ids[0], ids[len(ids)-1])
+	}
+	seen := make(map[int64]bool)
+	for _, id := range ids {
+		if seen[id] {
+			t.Errorf("match %d returned twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestMatchlistIteratorContext(t *testing.T) {
+	c := testMatchlistClient(250, time.Now(), time.Hour)
+	it := NewMatchlistIterator(c, region.NA1, "account", nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	for i := 0; i < maxMatchlistPage; i++ {
+		if _, err := it.Next(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cancel()
+	if _, err := it.Next(ctx); err != context.Canceled {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+	if len(c.calls) != 1 {
+		t.Errorf("got %d calls, want 1", len(c.calls))
+	}
+}
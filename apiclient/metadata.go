@@ -0,0 +1,133 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ResponseMetadata describes a single attempt of an API call. Calls that are
+// retried report one ResponseMetadata per attempt.
+type ResponseMetadata struct {
+	// Region is the platform or regional routing value that served the call.
+	Region string
+
+	// Method is the relative method path with all options stripped, and
+	// Uniquifier is the ratelimit uniquifier of the method, if any. Together
+	// they identify the method quota bucket.
+	Method     string
+	Uniquifier string
+
+	// StatusCode is the HTTP status, or zero if no response was received.
+	StatusCode int
+
+	// Header is the full response header, or nil if no response was received.
+	Header http.Header
+
+	// AppLimits and MethodLimits are the application and method rate limits
+	// reported by the response, sorted ascending by window. They are empty if
+	// the response did not include rate limit headers.
+	AppLimits    []RateLimitStatus
+	MethodLimits []RateLimitStatus
+
+	// RateLimitWait is the time spent waiting for quota from the
+	// ratelimit.Limiter before the request was sent.
+	RateLimitWait time.Duration
+
+	// Latency is the time between sending the request and receiving the
+	// response headers.
+	Latency time.Duration
+
+	// Err is the transport error, if the request could not be completed.
+	Err error
+}
+
+// RateLimitStatus is the usage of a single rate limit window, as reported by
+// the X-*-Rate-Limit and X-*-Rate-Limit-Count headers.
+type RateLimitStatus struct {
+	Window time.Duration // Length of the rate limit window.
+	Limit  int64         // Number of calls allowed in the window.
+	Count  int64         // Number of calls made in the current window.
+}
+
+// metadataCollectorKey is the context key of the metadata collector.
+type metadataCollectorKey struct{}
+
+// WithMetadataCollector returns a context that reports the ResponseMetadata of
+// every API call attempt made with it to the given function. The function may
+// be called concurrently if the context is shared by concurrent calls.
+func WithMetadataCollector(ctx context.Context, collect func(*ResponseMetadata)) context.Context {
+	return context.WithValue(ctx, metadataCollectorKey{}, collect)
+}
+
+// collectMetadata reports metadata to the collector attached to the context,
+// if any.
+func collectMetadata(ctx context.Context, quotaRegion, m, uniquifier string, wait, latency time.Duration, res *http.Response, err error) {
+	collect, ok := ctx.Value(metadataCollectorKey{}).(func(*ResponseMetadata))
+	if !ok {
+		return
+	}
+	meta := ResponseMetadata{
+		Region:        strings.ToUpper(quotaRegion),
+		Method:        m,
+		Uniquifier:    uniquifier,
+		RateLimitWait: wait,
+		Latency:       latency,
+		Err:           err,
+	}
+	if res != nil {
+		meta.StatusCode = res.StatusCode
+		meta.Header = res.Header
+		meta.AppLimits = parseRateLimits(res.Header.Get("X-App-Rate-Limit"), res.Header.Get("X-App-Rate-Limit-Count"))
+		meta.MethodLimits = parseRateLimits(res.Header.Get("X-Method-Rate-Limit"), res.Header.Get("X-Method-Rate-Limit-Count"))
+	}
+	collect(&meta)
+}
+
+// parseRateLimits combines rate limit headers like "20:1,100:120" and their
+// counts like "1:1,2:120" into statuses sorted by window. Malformed entries
+// are skipped.
+func parseRateLimits(limits, counts string) []RateLimitStatus {
+	countByWindow := make(map[int64]int64)
+	for _, piece := range strings.Split(counts, ",") {
+		if window, count, ok := parseRateLimitPiece(piece); ok {
+			countByWindow[window] = count
+		}
+	}
+	var res []RateLimitStatus
+	for _, piece := range strings.Split(limits, ",") {
+		window, limit, ok := parseRateLimitPiece(piece)
+		if !ok {
+			continue
+		}
+		res = append(res, RateLimitStatus{
+			Window: time.Duration(window) * time.Second,
+			Limit:  limit,
+			Count:  countByWindow[window],
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Window < res[j].Window
+	})
+	return res
+}
+
+// parseRateLimitPiece parses "value:seconds" into its parts.
+func parseRateLimitPiece(piece string) (seconds, value int64, ok bool) {
+	kv := strings.Split(strings.TrimSpace(piece), ":")
+	if len(kv) != 2 {
+		return 0, 0, false
+	}
+	value, err := strconv.ParseInt(kv[0], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seconds, err = strconv.ParseInt(kv[1], 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return seconds, value, true
+}
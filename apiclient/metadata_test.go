@@ -0,0 +1,50 @@
+package apiclient
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/testing/doertest"
+)
+
+func TestMetadataCollector(t *testing.T) {
+	header := make(http.Header)
+	header.Set("X-App-Rate-Limit", "100:120,20:1")
+	header.Set("X-App-Rate-Limit-Count", "1:1,7:120")
+	header.Set("X-Method-Rate-Limit", "2000:60")
+	header.Set("X-Method-Rate-Limit-Count", "3:60")
+	c := New("key", doertest.StaticResponse(http.StatusOK, header, "{}"), ratelimit.NewLimiter())
+
+	var got []*ResponseMetadata
+	ctx := WithMetadataCollector(context.Background(), func(m *ResponseMetadata) {
+		got = append(got, m)
+	})
+	if _, err := c.GetMatchTimelineV5(ctx, region.KR, "KR_1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("got %d metadata, want 1", len(got))
+	}
+	m := got[0]
+	if m.Region != "ASIA" || m.Method != "/lol/match/v5/matches" || m.Uniquifier != "timeline" || m.StatusCode != http.StatusOK {
+		t.Errorf("got %+v", m)
+	}
+	wantApp := []RateLimitStatus{
+		{Window: time.Second, Limit: 20, Count: 1},
+		{Window: 120 * time.Second, Limit: 100, Count: 7},
+	}
+	if !reflect.DeepEqual(m.AppLimits, wantApp) {
+		t.Errorf("got app limits %+v, want %+v", m.AppLimits, wantApp)
+	}
+	wantMethod := []RateLimitStatus{
+		{Window: 60 * time.Second, Limit: 2000, Count: 3},
+	}
+	if !reflect.DeepEqual(m.MethodLimits, wantMethod) {
+		t.Errorf("got method limits %+v, want %+v", m.MethodLimits, wantMethod)
+	}
+}
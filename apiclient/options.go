@@ -0,0 +1,87 @@
+package apiclient
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/yuhanfang/riot/constants/region"
+)
+
+// RequestHook inspects or modifies every request before it is sent. The
+// returned request is sent in place of the given request, which allows hooks
+// to attach values to the request context, e.g. for tracing. Returning an
+// error aborts the call before any quota is acquired.
+type RequestHook func(req *http.Request) (*http.Request, error)
+
+// ResponseHook inspects or modifies every response after it is received, and
+// before it is checked for errors and unmarshaled. The rate limiter has
+// already processed the response headers by the time the hook is called.
+// Returning an error fails the call.
+type ResponseHook func(req *http.Request, res *http.Response) error
+
+// WithBaseURL sends all calls for the given platform to the given base URL,
+// for example "http://localhost:8080", instead of the Riot API host for the
+// platform. Quota is still tracked per platform.
+func WithBaseURL(r region.Region, baseURL string) Option {
+	return func(c *client) {
+		c.baseURLs[strings.ToUpper(string(r))] = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithRoutingBaseURL sends all calls for the given regional cluster to the
+// given base URL, instead of the Riot API host for the cluster.
+func WithRoutingBaseURL(r region.Routing, baseURL string) Option {
+	return func(c *client) {
+		c.baseURLs[strings.ToUpper(string(r))] = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithDefaultBaseURL sends calls for every platform and regional cluster that
+// does not have a more specific base URL to the given base URL. This is
+// useful for pointing the client at a single mock server or proxy.
+func WithDefaultBaseURL(baseURL string) Option {
+	return func(c *client) {
+		c.defaultBaseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request.
+func WithUserAgent(userAgent string) Option {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// WithHeader sets the given header on every request. The X-Riot-Token header
+// is always set to the client's key and cannot be overridden.
+func WithHeader(key, value string) Option {
+	return func(c *client) {
+		c.header.Set(key, value)
+	}
+}
+
+// WithRequestHook adds a hook that is called on every request. Hooks are
+// called in the order they are added.
+func WithRequestHook(h RequestHook) Option {
+	return func(c *client) {
+		c.requestHooks = append(c.requestHooks, h)
+	}
+}
+
+// WithResponseHook adds a hook that is called on every response. Hooks are
+// called in the order they are added.
+func WithResponseHook(h ResponseHook) Option {
+	return func(c *client) {
+		c.responseHooks = append(c.responseHooks, h)
+	}
+}
+
+// baseURL returns the base URL that serves the given platform or regional
+// cluster, falling back to the given default host.
+func (c *client) baseURL(quotaRegion string, host func() string) string {
+	if u, ok := c.baseURLs[strings.ToUpper(quotaRegion)]; ok {
+		return u
+	}
+	if c.defaultBaseURL != "" {
+		return c.defaultBaseURL
+	}
+	return host()
+}
@@ -0,0 +1,131 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/testing/doertest"
+)
+
+func TestOptions(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Riot-Token"); got != "key" {
+			t.Errorf("got X-Riot-Token %q, want key", got)
+		}
+		if got := r.Header.Get("User-Agent"); got != "riot-test" {
+			t.Errorf("got User-Agent %q, want riot-test", got)
+		}
+		if got := r.Header.Get("X-Trace"); got != "abc" {
+			t.Errorf("got X-Trace %q, want abc", got)
+		}
+		if r.URL.Path != "/lol/summoner/v4/summoners/by-puuid/puuid" {
+			t.Errorf("got path %q", r.URL.Path)
+		}
+		w.Header().Set("X-Served-By", "mock")
+		fmt.Fprint(w, `{"name": "waddlechirp"}`)
+	}))
+	defer ts.Close()
+
+	var requests, responses int
+	c := New("key", http.DefaultClient, ratelimit.NewLimiter(),
+		WithBaseURL(region.NA1, ts.URL+"/"),
+		WithUserAgent("riot-test"),
+		WithHeader("X-Trace", "abc"),
+		WithRequestHook(func(req *http.Request) (*http.Request, error) {
+			requests++
+			return req, nil
+		}),
+		WithResponseHook(func(req *http.Request, res *http.Response) error {
+			responses++
+			if got := res.Header.Get("X-Served-By"); got != "mock" {
+				t.Errorf("got X-Served-By %q, want mock", got)
+			}
+			return nil
+		}),
+	)
+	got, err := c.GetBySummonerPUUID(context.Background(), region.NA1, "puuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "waddlechirp" {
+		t.Errorf("got name %q, want waddlechirp", got.Name)
+	}
+	if requests != 1 || responses != 1 {
+		t.Errorf("got %d request hooks and %d response hooks, want 1 each", requests, responses)
+	}
+}
+
+func TestRequestHookAborts(t *testing.T) {
+	errAbort := errors.New("abort")
+	c := New("key", doertest.StaticResponse(http.StatusOK, nil, "{}"), ratelimit.NewLimiter(),
+		WithRequestHook(func(req *http.Request) (*http.Request, error) {
+			return nil, errAbort
+		}),
+	)
+	_, err := c.GetBySummonerPUUID(context.Background(), region.NA1, "puuid")
+	if err != errAbort {
+		t.Errorf("got %v, want %v", err, errAbort)
+	}
+}
+
+func TestDefaultBaseURL(t *testing.T) {
+	var hosts []string
+	record := doertest.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		hosts = append(hosts, req.URL.Host)
+		return doertest.StaticResponse(http.StatusOK, nil, "{}")(req)
+	})
+	c := New("key", record, ratelimit.NewLimiter(),
+		WithDefaultBaseURL("http://mock"),
+		WithRoutingBaseURL(region.Europe, "http://europe-proxy"),
+	)
+	ctx := context.Background()
+	if _, err := c.GetMatchV5(ctx, region.NA1, "NA1_1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetMatchV5(ctx, region.EUW1, "EUW1_1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts) != 2 || hosts[0] != "mock" || hosts[1] != "europe-proxy" {
+		t.Errorf("got hosts %v, want [mock europe-proxy]", hosts)
+	}
+}
+
+// priorityLimiter records the priority of each acquisition.
+type priorityLimiter struct {
+	ratelimit.Limiter
+	priorities []ratelimit.Priority
+}
+
+func (l *priorityLimiter) Acquire(ctx context.Context, inv ratelimit.Invocation) (ratelimit.Done, ratelimit.Cancel, error) {
+	l.priorities = append(l.priorities, inv.Priority)
+	return l.Limiter.Acquire(ctx, inv)
+}
+
+func TestPriorityFromContext(t *testing.T) {
+	limiter := &priorityLimiter{Limiter: ratelimit.NewLimiter()}
+	c := New("key", responseSequence(http.StatusOK), limiter)
+
+	ctx := context.Background()
+	if _, err := c.GetBySummonerPUUID(ctx, region.NA1, "puuid"); err != nil {
+		t.Fatal(err)
+	}
+	ctx = ratelimit.WithPriority(ctx, ratelimit.PriorityInteractive)
+	if _, err := c.GetBySummonerPUUID(ctx, region.NA1, "puuid"); err != nil {
+		t.Fatal(err)
+	}
+	want := []ratelimit.Priority{ratelimit.PriorityNormal, ratelimit.PriorityInteractive}
+	if len(limiter.priorities) != len(want) {
+		t.Fatalf("got priorities %v, want %v", limiter.priorities, want)
+	}
+	for i := range want {
+		if limiter.priorities[i] != want[i] {
+			t.Errorf("got priorities %v, want %v", limiter.priorities, want)
+		}
+	}
+}
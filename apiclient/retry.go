@@ -0,0 +1,122 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy configures automatic retries of calls that fail with a
+// retryable HTTP status. Every attempt re-acquires quota from the client's
+// ratelimit.Limiter, so retries are rate limited exactly like first attempts.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts per call, including the
+	// first. Values less than two disable retries.
+	MaxAttempts int
+
+	// MinBackoff is the delay before the first retry. The delay doubles after
+	// every attempt, up to MaxBackoff.
+	MinBackoff time.Duration
+
+	// MaxBackoff is the maximum delay between attempts, before jitter. Zero
+	// means that the delay is unbounded.
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction of each delay, between 0 and 1, that is
+	// randomized. For example, a Jitter of 0.2 shortens each delay by a random
+	// amount of up to 20%.
+	Jitter float64
+
+	// HonorRetryAfter waits at least as long as the Retry-After header of a
+	// failed attempt, if the header is set.
+	HonorRetryAfter bool
+
+	// Statuses lists the HTTP statuses that are retried. Any other status, and
+	// any error that is not an *APIError, is returned immediately. Calls that
+	// are not GETs, such as creating tournament codes, are only retried on
+	// HTTP 429, since any other failure may come after Riot acted on the call.
+	Statuses map[int]bool
+}
+
+// DefaultRetryPolicy retries rate limited and transient server errors up to
+// three times with exponential backoff, honoring Retry-After.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:     4,
+	MinBackoff:      time.Second,
+	MaxBackoff:      30 * time.Second,
+	Jitter:          0.2,
+	HonorRetryAfter: true,
+	Statuses: map[int]bool{
+		429: true,
+		500: true,
+		502: true,
+		503: true,
+		504: true,
+	},
+}
+
+// WithRetryPolicy configures the client to retry failed calls using the given
+// policy. By default, the client makes exactly one attempt per call.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(c *client) {
+		c.retry = p
+	}
+}
+
+// retryDelay returns how long to wait before the next attempt, given that the
+// attempt with the 1-based index failed with err. Returns false if the call
+// should not be retried.
+func (p *RetryPolicy) retryDelay(attempt int, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || !p.Statuses[apiErr.StatusCode] {
+		return 0, false
+	}
+
+	delay := p.MinBackoff
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxBackoff > 0 && delay >= p.MaxBackoff {
+			delay = p.MaxBackoff
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if p.Jitter > 0 && delay > 0 {
+		delay -= time.Duration(rand.Float64() * p.Jitter * float64(delay))
+	}
+	if p.HonorRetryAfter && apiErr.RetryAfter > delay {
+		delay = apiErr.RetryAfter
+	}
+	return delay, true
+}
+
+// idempotent returns true if a call with the given HTTP method that failed
+// with err can be retried without repeating its side effects. GETs have none,
+// and other calls are rejected by Riot before they take effect only if they
+// are rate limited.
+func idempotent(httpMethod string, err error) bool {
+	if httpMethod == http.MethodGet {
+		return true
+	}
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+// sleep blocks for the given duration, or until the context is cancelled.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,113 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/testing/doertest"
+)
+
+// countingLimiter counts the number of acquisitions from the wrapped limiter.
+type countingLimiter struct {
+	ratelimit.Limiter
+	acquired int32
+}
+
+func (l *countingLimiter) Acquire(ctx context.Context, inv ratelimit.Invocation) (ratelimit.Done, ratelimit.Cancel, error) {
+	atomic.AddInt32(&l.acquired, 1)
+	return l.Limiter.Acquire(ctx, inv)
+}
+
+// responseSequence returns a Doer that responds with the given statuses in
+// order, and with the last status once the sequence is exhausted.
+func responseSequence(statuses ...int) doertest.DoerFunc {
+	var calls int32
+	return func(req *http.Request) (*http.Response, error) {
+		i := int(atomic.AddInt32(&calls, 1)) - 1
+		if i >= len(statuses) {
+			i = len(statuses) - 1
+		}
+		return doertest.Response(req, statuses[i], nil, `{"name": "waddlechirp"}`), nil
+	}
+}
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  time.Millisecond,
+	MaxBackoff:  2 * time.Millisecond,
+	Statuses:    map[int]bool{503: true},
+}
+
+func TestRetrySucceeds(t *testing.T) {
+	limiter := &countingLimiter{Limiter: ratelimit.NewLimiter()}
+	c := New("key", responseSequence(503, 503, 200), limiter, WithRetryPolicy(testRetryPolicy))
+	got, err := c.GetBySummonerPUUID(context.Background(), region.NA1, "puuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "waddlechirp" {
+		t.Errorf("got name %q, want waddlechirp", got.Name)
+	}
+	if limiter.acquired != 3 {
+		t.Errorf("acquired quota %d times, want once per attempt (3)", limiter.acquired)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	limiter := &countingLimiter{Limiter: ratelimit.NewLimiter()}
+	c := New("key", responseSequence(503), limiter, WithRetryPolicy(testRetryPolicy))
+	_, err := c.GetBySummonerPUUID(context.Background(), region.NA1, "puuid")
+	if !errors.Is(err, ErrServiceUnavailable) {
+		t.Fatalf("got %v, want ErrServiceUnavailable", err)
+	}
+	if limiter.acquired != 3 {
+		t.Errorf("made %d attempts, want 3", limiter.acquired)
+	}
+}
+
+func TestRetrySkipsUnlistedStatus(t *testing.T) {
+	limiter := &countingLimiter{Limiter: ratelimit.NewLimiter()}
+	c := New("key", responseSequence(404, 200), limiter, WithRetryPolicy(testRetryPolicy))
+	_, err := c.GetBySummonerPUUID(context.Background(), region.NA1, "puuid")
+	if !errors.Is(err, ErrDataNotFound) {
+		t.Fatalf("got %v, want ErrDataNotFound", err)
+	}
+	if limiter.acquired != 1 {
+		t.Errorf("made %d attempts, want 1", limiter.acquired)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := RetryPolicy{
+		MaxAttempts:     5,
+		MinBackoff:      time.Second,
+		MaxBackoff:      3 * time.Second,
+		HonorRetryAfter: true,
+		Statuses:        map[int]bool{429: true},
+	}
+	for _, test := range []struct {
+		attempt    int
+		retryAfter time.Duration
+		want       time.Duration
+	}{
+		{1, 0, time.Second},
+		{2, 0, 2 * time.Second},
+		{3, 0, 3 * time.Second},
+		{4, 0, 3 * time.Second},
+		{1, 10 * time.Second, 10 * time.Second},
+	} {
+		got, ok := p.retryDelay(test.attempt, &APIError{StatusCode: 429, RetryAfter: test.retryAfter})
+		if !ok || got != test.want {
+			t.Errorf("retryDelay(%d) with Retry-After %v = %v, %v; want %v, true", test.attempt, test.retryAfter, got, ok, test.want)
+		}
+	}
+	if _, ok := p.retryDelay(5, &APIError{StatusCode: 429}); ok {
+		t.Error("retryDelay after MaxAttempts should not retry")
+	}
+}
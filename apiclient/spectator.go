@@ -32,23 +32,40 @@ type Observer struct {
 }
 
 type CurrentGameParticipant struct {
-	ProfileIconId int64                              `json:"profileIconId"` // The ID of the profile icon used by this participant
-	ChampionId    int64                              `json:"championId"`    // The ID of the champion played by this participant
-	SummonerName  string                             `json:"summonerName"`  // The summoner name of this participant
-	Runes         []CurrentGameParticipantRuneDTO    `json:"runes"`         // The runes used by this participant
-	Bot           bool                               `json:"bot"`           // Flag indicating whether or not this participant is a bot
-	TeamId        int64                              `json:"teamId"`        // The team ID of this participant, indicating the participant's team
-	Spell2Id      int64                              `json:"spell2Id"`      // The ID of the second summoner spell used by this participant
-	Masteries     []CurrentGameParticipantMasteryDTO `json:"masteries"`     // The masteries used by this participant
-	Spell1Id      int64                              `json:"spell1Id"`      // The ID of the first summoner spell used by this participant
-	SummonerId    string                             `json:"summonerId"`    // The encrypted summoner ID of this participant
-	Perks         Perks                              `json:"perks"`
+	ProfileIconId            int64                     `json:"profileIconId"`            // The ID of the profile icon used by this participant
+	ChampionId               int64                     `json:"championId"`               // The ID of the champion played by this participant
+	SummonerName             string                    `json:"summonerName"`             // The summoner name of this participant. Not returned by spectator-v5.
+	RiotID                   string                    `json:"riotId"`                   // The Riot ID of this participant, e.g. "Name#NA1". Only returned by spectator-v5.
+	PUUID                    string                    `json:"puuid"`                    // The PUUID of this participant. Only returned by spectator-v5.
+	Bot                      bool                      `json:"bot"`                      // Flag indicating whether or not this participant is a bot
+	TeamId                   int64                     `json:"teamId"`                   // The team ID of this participant, indicating the participant's team
+	Spell2Id                 int64                     `json:"spell2Id"`                 // The ID of the second summoner spell used by this participant
+	Spell1Id                 int64                     `json:"spell1Id"`                 // The ID of the first summoner spell used by this participant
+	SummonerId               string                    `json:"summonerId"`               // The encrypted summoner ID of this participant
+	Perks                    Perks                     `json:"perks"`                    // The rune page used by this participant
+	GameCustomizationObjects []GameCustomizationObject `json:"gameCustomizationObjects"` // Custom game modifiers for this participant
+
+	// Deprecated: Runes were removed from the game in 2017 and are no longer
+	// returned.
+	Runes []CurrentGameParticipantRuneDTO `json:"runes"`
+
+	// Deprecated: Masteries were removed from the game in 2017 and are no
+	// longer returned.
+	Masteries []CurrentGameParticipantMasteryDTO `json:"masteries"`
 }
 
+// Perks is a rune page. Decoding matches JSON keys case-insensitively, but
+// encoding does not, so the tags spell Riot's keys exactly for consumers of
+// re-encoded games, such as clients of the mockapi server.
 type Perks struct {
-	PerkIDs      []int64 `json:"perkIDs"`
-	PerkStyle    int64   `json:"perkStyle"`
-	PerkSubStyle int64   `json:"perkSubStyle"`
+	PerkIDs      []int64 `json:"perkIds"`      // The IDs of the runes, including stat shards
+	PerkStyle    int64   `json:"perkStyle"`    // The ID of the primary rune path
+	PerkSubStyle int64   `json:"perkSubStyle"` // The ID of the secondary rune path
+}
+
+type GameCustomizationObject struct {
+	Category string `json:"category"` // Category identifier for the customization
+	Content  string `json:"content"`  // Game customization content
 }
 
 type CurrentGameParticipantRuneDTO struct {
@@ -67,6 +84,12 @@ func (c *client) GetCurrentGameInfoBySummoner(ctx context.Context, r region.Regi
 	return &res, err
 }
 
+func (c *client) GetCurrentGameInfoByPUUID(ctx context.Context, r region.Region, puuid string) (*CurrentGameInfo, error) {
+	var res CurrentGameInfo
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/spectator/v5/active-games/by-summoner", fmt.Sprintf("/%s", puuid), nil, &res)
+	return &res, err
+}
+
 type FeaturedGames struct {
 	ClientRefreshInterval int64                 `json:"clientRefreshInterval",datastore:",noindex"` // The suggested interval to wait before requesting FeaturedGames again
 	GameList              []FeaturedGameInfoDTO `json:"gameList",datastore:",noindex"`              // 	The list of featured games
@@ -89,7 +112,9 @@ type FeaturedGameInfoDTO struct {
 type FeaturedGameParticipantDTO struct {
 	ProfileIconId int64  `json:"profileIconId"` // The ID of the profile icon used by this participant
 	ChampionId    int64  `json:"championId"`    // The ID of the champion played by this participant
-	SummonerName  string `json:"summonerName"`  // The summoner name of this participant
+	SummonerName  string `json:"summonerName"`  // The summoner name of this participant. Not returned by spectator-v5.
+	RiotID        string `json:"riotId"`        // The Riot ID of this participant. Only returned by spectator-v5.
+	PUUID         string `json:"puuid"`         // The PUUID of this participant. Only returned by spectator-v5.
 	Bot           bool   `json:"bot"`           // Flag indicating whether or not this participant is a bot
 	Spell2Id      int64  `json:"spell2Id"`      // The ID of the second summoner spell used by this participant
 	TeamId        int64  `json:"teamId"`        // The team ID of this participant, indicating the participant's team
@@ -102,3 +127,9 @@ func (c *client) GetFeaturedGames(ctx context.Context, r region.Region) (*Featur
 	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/spectator/v4/featured-games", "", nil, &res)
 	return &res, err
 }
+
+func (c *client) GetFeaturedGamesV5(ctx context.Context, r region.Region) (*FeaturedGames, error) {
+	var res FeaturedGames
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/spectator/v5/featured-games", "", nil, &res)
+	return &res, err
+}
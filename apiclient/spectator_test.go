@@ -0,0 +1,74 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/testing/doertest"
+)
+
+const currentGameV5 = `{
+  "gameId": 4567,
+  "platformId": "NA1",
+  "participants": [{
+    "puuid": "puuid",
+    "riotId": "waddlechirp#NA1",
+    "championId": 22,
+    "teamId": 100,
+    "perks": {
+      "perkIds": [8010, 9111, 9104, 8299, 8226, 8210, 5005, 5008, 5002],
+      "perkStyle": 8000,
+      "perkSubStyle": 8200
+    }
+  }]
+}`
+
+func TestGetCurrentGameInfoByPUUID(t *testing.T) {
+	var path string
+	doer := doertest.StaticResponse(http.StatusOK, nil, currentGameV5)
+	c := New("key", doertest.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		path = req.URL.Path
+		return doer(req)
+	}), ratelimit.NewLimiter())
+
+	game, err := c.GetCurrentGameInfoByPUUID(context.Background(), region.NA1, "puuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/lol/spectator/v5/active-games/by-summoner/puuid" {
+		t.Errorf("got path %s", path)
+	}
+	p := game.Participants[0]
+	if p.PUUID != "puuid" || p.RiotID != "waddlechirp#NA1" {
+		t.Errorf("got PUUID %q and Riot ID %q", p.PUUID, p.RiotID)
+	}
+	want := Perks{
+		PerkIDs:      []int64{8010, 9111, 9104, 8299, 8226, 8210, 5005, 5008, 5002},
+		PerkStyle:    8000,
+		PerkSubStyle: 8200,
+	}
+	if !reflect.DeepEqual(p.Perks, want) {
+		t.Errorf("got perks %+v, want %+v", p.Perks, want)
+	}
+}
+
+func TestPerksEncodeRiotKeys(t *testing.T) {
+	b, err := json.Marshal(Perks{PerkIDs: []int64{8010}, PerkStyle: 8000, PerkSubStyle: 8200})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(b, &keys); err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []string{"perkIds", "perkStyle", "perkSubStyle"} {
+		if _, ok := keys[k]; !ok {
+			t.Errorf("key %q is missing from %s", k, b)
+		}
+	}
+}
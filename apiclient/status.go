@@ -0,0 +1,263 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/region"
+)
+
+// PlatformStatus is the status of a platform, including scheduled and ongoing
+// maintenances and incidents.
+type PlatformStatus struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Locales      []string `json:"locales"`
+	Maintenances []Status `json:"maintenances"`
+	Incidents    []Status `json:"incidents"`
+}
+
+// Degraded returns true if the platform has an incident, or maintenance in
+// progress.
+func (p *PlatformStatus) Degraded() bool {
+	if len(p.Incidents) > 0 {
+		return true
+	}
+	for _, m := range p.Maintenances {
+		if m.MaintenanceStatus == "in_progress" {
+			return true
+		}
+	}
+	return false
+}
+
+// Status is a maintenance or incident.
+type Status struct {
+	ID                int       `json:"id"`
+	MaintenanceStatus string    `json:"maintenance_status"` // One of scheduled, in_progress or complete, for maintenances.
+	IncidentSeverity  string    `json:"incident_severity"`  // One of info, warning or critical, for incidents.
+	Titles            []Content `json:"titles"`
+	Updates           []Update  `json:"updates"`
+	CreatedAt         time.Time `json:"created_at"`
+	ArchiveAt         time.Time `json:"archive_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	Platforms         []string  `json:"platforms"` // One or more of windows, macos, android, ios, ps4, xbone or switch.
+}
+
+// Title returns the title in the given locale, e.g. "en_US", or the first
+// title if there is none in the locale.
+func (s *Status) Title(locale string) string {
+	return localize(s.Titles, locale)
+}
+
+// Update is a message posted about a maintenance or incident.
+type Update struct {
+	ID               int       `json:"id"`
+	Author           string    `json:"author"`
+	Publish          bool      `json:"publish"`
+	PublishLocations []string  `json:"publish_locations"` // One or more of riotclient, riotstatus or game.
+	Translations     []Content `json:"translations"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Message returns the message in the given locale, e.g. "en_US", or the first
+// message if there is none in the locale.
+func (u *Update) Message(locale string) string {
+	return localize(u.Translations, locale)
+}
+
+// Content is localized text.
+type Content struct {
+	Locale  string `json:"locale"`
+	Content string `json:"content"`
+}
+
+// localize returns the content in the given locale, or the first content if
+// there is none in the locale.
+func localize(contents []Content, locale string) string {
+	for _, c := range contents {
+		if c.Locale == locale {
+			return c.Content
+		}
+	}
+	if len(contents) > 0 {
+		return contents[0].Content
+	}
+	return ""
+}
+
+func (c *client) GetPlatformStatus(ctx context.Context, r region.Region) (*PlatformStatus, error) {
+	var res PlatformStatus
+	_, err := c.dispatchAndUnmarshal(ctx, r, "/lol/status/v4/platform-data", "", nil, &res)
+	return &res, err
+}
+
+// StatusEventType is the kind of change reported by a StatusWatcher.
+type StatusEventType string
+
+const (
+	// StatusCreated means that a maintenance or incident was posted, or was
+	// already posted when the watcher started.
+	StatusCreated StatusEventType = "created"
+
+	// StatusUpdated means that a maintenance or incident changed, for example
+	// by a new update message or a change in severity.
+	StatusUpdated StatusEventType = "updated"
+
+	// StatusResolved means that a maintenance completed, or that a maintenance
+	// or incident was removed from the platform status.
+	StatusResolved StatusEventType = "resolved"
+
+	// StatusPollFailed means that the platform status could not be retrieved.
+	StatusPollFailed StatusEventType = "poll-failed"
+)
+
+// StatusEvent is a change in platform status.
+type StatusEvent struct {
+	Type   StatusEventType
+	Region region.Region
+
+	// Maintenance is true if Status is a maintenance, and false if it is an
+	// incident.
+	Maintenance bool
+
+	// Status is the maintenance or incident that changed. For resolved events,
+	// it is the last version that was seen.
+	Status Status
+
+	// Err is the error for StatusPollFailed events.
+	Err error
+}
+
+func (e StatusEvent) String() string {
+	kind := "incident"
+	if e.Maintenance {
+		kind = "maintenance"
+	}
+	if e.Type == StatusPollFailed {
+		return fmt.Sprintf("%s status %s: %v", e.Region, e.Type, e.Err)
+	}
+	return fmt.Sprintf("%s %s %d %s: %s", e.Region, kind, e.Status.ID, e.Type, e.Status.Title("en_US"))
+}
+
+// StatusWatcher polls the platform status of regions and reports changes. It
+// is illegal to construct an instance directly. Use NewStatusWatcher to return
+// a valid instance.
+type StatusWatcher struct {
+	c        Client
+	interval time.Duration
+	regions  []region.Region
+}
+
+// NewStatusWatcher returns a watcher that polls the status of the given
+// regions, or of region.All() if none are given, at the given interval.
+func NewStatusWatcher(c Client, interval time.Duration, regions ...region.Region) *StatusWatcher {
+	if len(regions) == 0 {
+		regions = region.All()
+	}
+	return &StatusWatcher{
+		c:        c,
+		interval: interval,
+		regions:  regions,
+	}
+}
+
+// statusKey identifies a maintenance or incident within a region.
+type statusKey struct {
+	maintenance bool
+	id          int
+}
+
+// Watch polls until the context is done, and sends changes on the returned
+// channel, which is closed when watching stops. The first poll reports
+// existing maintenances and incidents as created. The channel is unbuffered,
+// so the watcher does not poll again until the events of the previous poll
+// have been received.
+func (w *StatusWatcher) Watch(ctx context.Context) <-chan StatusEvent {
+	events := make(chan StatusEvent)
+	go func() {
+		defer close(events)
+		seen := make(map[region.Region]map[statusKey]Status)
+		for _, r := range w.regions {
+			seen[r] = make(map[statusKey]Status)
+		}
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			for _, r := range w.regions {
+				for _, e := range w.poll(ctx, r, seen[r]) {
+					select {
+					case events <- e:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// poll retrieves the status of the region, updates the seen statuses, and
+// returns the changes.
+func (w *StatusWatcher) poll(ctx context.Context, r region.Region, seen map[statusKey]Status) []StatusEvent {
+	status, err := w.c.GetPlatformStatus(ctx, r)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return []StatusEvent{{Type: StatusPollFailed, Region: r, Err: err}}
+	}
+
+	var events []StatusEvent
+	current := make(map[statusKey]bool)
+	check := func(s Status, maintenance bool) {
+		key := statusKey{maintenance: maintenance, id: s.ID}
+		// Completed maintenances are resolved, even if they are still listed.
+		if maintenance && s.MaintenanceStatus == "complete" {
+			if _, ok := seen[key]; ok {
+				events = append(events, StatusEvent{Type: StatusResolved, Region: r, Maintenance: maintenance, Status: s})
+				delete(seen, key)
+			}
+			return
+		}
+		current[key] = true
+		prev, ok := seen[key]
+		seen[key] = s
+		switch {
+		case !ok:
+			events = append(events, StatusEvent{Type: StatusCreated, Region: r, Maintenance: maintenance, Status: s})
+		case statusChanged(&prev, &s):
+			events = append(events, StatusEvent{Type: StatusUpdated, Region: r, Maintenance: maintenance, Status: s})
+		}
+	}
+	for _, s := range status.Maintenances {
+		check(s, true)
+	}
+	for _, s := range status.Incidents {
+		check(s, false)
+	}
+	for key, s := range seen {
+		if !current[key] {
+			events = append(events, StatusEvent{Type: StatusResolved, Region: r, Maintenance: key.maintenance, Status: s})
+			delete(seen, key)
+		}
+	}
+	return events
+}
+
+// statusChanged returns true if the status has changed in a way that is
+// visible to players.
+func statusChanged(prev, s *Status) bool {
+	return !prev.UpdatedAt.Equal(s.UpdatedAt) ||
+		len(prev.Updates) != len(s.Updates) ||
+		prev.MaintenanceStatus != s.MaintenanceStatus ||
+		prev.IncidentSeverity != s.IncidentSeverity
+}
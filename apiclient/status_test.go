@@ -0,0 +1,68 @@
+package apiclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/region"
+)
+
+// statusClient serves a scripted sequence of platform statuses, repeating the
+// last one.
+type statusClient struct {
+	Client
+	statuses []PlatformStatus
+	polls    int
+}
+
+func (c *statusClient) GetPlatformStatus(ctx context.Context, r region.Region) (*PlatformStatus, error) {
+	i := c.polls
+	if i >= len(c.statuses) {
+		i = len(c.statuses) - 1
+	}
+	c.polls++
+	return &c.statuses[i], nil
+}
+
+func TestStatusWatcher(t *testing.T) {
+	created := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	incident := Status{
+		ID:               1,
+		IncidentSeverity: "warning",
+		Titles:           []Content{{Locale: "en_US", Content: "Login issues"}},
+		CreatedAt:        created,
+		UpdatedAt:        created,
+	}
+	updated := incident
+	updated.Updates = []Update{{ID: 2, Translations: []Content{{Locale: "en_US", Content: "Investigating"}}}}
+	updated.UpdatedAt = created.Add(time.Minute)
+
+	c := &statusClient{statuses: []PlatformStatus{
+		{},
+		{Incidents: []Status{incident}},
+		{Incidents: []Status{incident}},
+		{Incidents: []Status{updated}},
+		{},
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := NewStatusWatcher(c, time.Millisecond, region.NA1).Watch(ctx)
+
+	for _, want := range []StatusEventType{StatusCreated, StatusUpdated, StatusResolved} {
+		e := <-events
+		if e.Type != want || e.Region != region.NA1 || e.Maintenance || e.Status.ID != 1 {
+			t.Errorf("got event %v, want NA1 incident 1 %s", e, want)
+		}
+	}
+	if s := (&PlatformStatus{Incidents: []Status{updated}}); !s.Degraded() {
+		t.Error("got not degraded, want degraded with an incident")
+	}
+	if got := updated.Updates[0].Message("ko_KR"); got != "Investigating" {
+		t.Errorf("got message %q, want fallback to first translation", got)
+	}
+
+	cancel()
+	for range events {
+	}
+}
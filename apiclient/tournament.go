@@ -0,0 +1,277 @@
+package apiclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/external"
+	"github.com/yuhanfang/riot/ratelimit"
+)
+
+// TournamentClient accesses the tournament API, which creates tournament codes
+// for custom games and reports their results. Use NewTournamentClient or
+// NewTournamentStubClient to retrieve a valid instance.
+//
+// The tournament API is served by the Americas regional cluster for every
+// platform, so calls are sent to, and quota is tracked under, region.Americas.
+// Use WithRoutingBaseURL(region.Americas, ...) to send calls elsewhere.
+type TournamentClient interface {
+	// RegisterProvider registers the callback URL that receives game results
+	// for games played on the given platform, and returns the provider ID.
+	// The URL must use port 80 for http or port 443 for https.
+	RegisterProvider(ctx context.Context, r region.Region, callbackURL string) (int, error)
+
+	// RegisterTournament registers a tournament for the given provider, and
+	// returns the tournament ID.
+	RegisterTournament(ctx context.Context, providerID int, name string) (int64, error)
+
+	// CreateTournamentCodes creates count tournament codes, between 1 and 1000,
+	// for the given tournament.
+	CreateTournamentCodes(ctx context.Context, tournamentID int64, count int, params TournamentCodeParameters) ([]string, error)
+
+	// GetTournamentCode returns the details of the given tournament code.
+	GetTournamentCode(ctx context.Context, code string) (*TournamentCode, error)
+
+	// UpdateTournamentCode changes the settings of the given tournament code.
+	// The stub returns ErrNotSupportedByStub.
+	UpdateTournamentCode(ctx context.Context, code string, params TournamentCodeUpdateParameters) error
+
+	// GetLobbyEvents returns the lobby events of the given tournament code,
+	// oldest first.
+	GetLobbyEvents(ctx context.Context, code string) ([]LobbyEvent, error)
+
+	// GetTournamentGames returns the results of the games played with the
+	// given tournament code. The stub returns ErrNotSupportedByStub.
+	GetTournamentGames(ctx context.Context, code string) ([]TournamentGame, error)
+}
+
+// TournamentPickType is the champion select mode of a tournament code.
+type TournamentPickType string
+
+const (
+	PickBlind           TournamentPickType = "BLIND_PICK"
+	PickDraft           TournamentPickType = "DRAFT_MODE"
+	PickAllRandom       TournamentPickType = "ALL_RANDOM"
+	PickTournamentDraft TournamentPickType = "TOURNAMENT_DRAFT"
+)
+
+// TournamentMapType is the map of a tournament code.
+type TournamentMapType string
+
+const (
+	MapSummonersRift TournamentMapType = "SUMMONERS_RIFT"
+	MapHowlingAbyss  TournamentMapType = "HOWLING_ABYSS"
+)
+
+// TournamentSpectatorType controls who may spectate games of a tournament
+// code.
+type TournamentSpectatorType string
+
+const (
+	SpectatorNone      TournamentSpectatorType = "NONE"
+	SpectatorLobbyOnly TournamentSpectatorType = "LOBBYONLY"
+	SpectatorAll       TournamentSpectatorType = "ALL"
+)
+
+// TournamentCodeParameters configures the games played with new tournament
+// codes.
+type TournamentCodeParameters struct {
+	AllowedParticipants []string                `json:"allowedParticipants,omitempty"` // PUUIDs of the players that may join. Anyone may join if empty.
+	Metadata            string                  `json:"metadata,omitempty"`            // Passed back in the game result callback.
+	TeamSize            int                     `json:"teamSize"`                      // Between 1 and 5.
+	PickType            TournamentPickType      `json:"pickType"`
+	MapType             TournamentMapType       `json:"mapType"`
+	SpectatorType       TournamentSpectatorType `json:"spectatorType"`
+	EnoughPlayers       bool                    `json:"enoughPlayers"` // Requires AllowedParticipants to fill both teams.
+}
+
+// TournamentCodeUpdateParameters changes the settings of an existing
+// tournament code.
+type TournamentCodeUpdateParameters struct {
+	AllowedParticipants []string                `json:"allowedParticipants,omitempty"` // PUUIDs of the players that may join.
+	PickType            TournamentPickType      `json:"pickType"`
+	MapType             TournamentMapType       `json:"mapType"`
+	SpectatorType       TournamentSpectatorType `json:"spectatorType"`
+}
+
+// TournamentCode describes a tournament code.
+type TournamentCode struct {
+	ID           int64    `json:"id"`
+	Code         string   `json:"code"`
+	ProviderID   int      `json:"providerId"`
+	TournamentID int64    `json:"tournamentId"`
+	Region       string   `json:"region"` // Tournament region, e.g. "NA".
+	Map          string   `json:"map"`
+	PickType     string   `json:"pickType"`
+	Spectators   string   `json:"spectators"`
+	TeamSize     int      `json:"teamSize"`
+	LobbyName    string   `json:"lobbyName"`
+	Password     string   `json:"password"`
+	Metadata     string   `json:"metaData"`
+	Participants []string `json:"participants"` // PUUIDs of the players that may join.
+}
+
+// LobbyEvent is an event in the lobby of a tournament code, e.g.
+// "PracticeGameCreatedEvent" or "PlayerJoinedGameEvent".
+type LobbyEvent struct {
+	Timestamp string `json:"timestamp"` // Unix milliseconds.
+	EventType string `json:"eventType"`
+	PUUID     string `json:"puuid"` // Empty for events that are not about a player.
+}
+
+// Time returns the time of the event, or the zero time if the timestamp is
+// malformed.
+func (e *LobbyEvent) Time() time.Time {
+	ms, err := strconv.ParseInt(e.Timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, ms*int64(time.Millisecond))
+}
+
+// TournamentGame is the result of a game played with a tournament code. It is
+// returned by GetTournamentGames, and is the body of the callback that Riot
+// sends to the provider URL when a game completes.
+type TournamentGame struct {
+	StartTime   int64            `json:"startTime"` // Unix milliseconds. Only set by the callback.
+	ShortCode   string           `json:"shortCode"` // The tournament code.
+	Metadata    string           `json:"metaData"`
+	GameID      int64            `json:"gameId"`
+	GameName    string           `json:"gameName"`
+	GameType    string           `json:"gameType"`
+	GameMap     int              `json:"gameMap"`
+	GameMode    string           `json:"gameMode"`
+	Region      string           `json:"region"` // Platform that hosted the game, e.g. "NA1".
+	WinningTeam []TournamentTeam `json:"winningTeam"`
+	LosingTeam  []TournamentTeam `json:"losingTeam"`
+}
+
+// TournamentTeam is a player on a team of a tournament game.
+type TournamentTeam struct {
+	PUUID string `json:"puuid"`
+}
+
+// tournamentClient is the internal implementation of TournamentClient.
+type tournamentClient struct {
+	c *client
+
+	// api is the path prefix of the methods, which selects between the
+	// tournament API and the stub.
+	api  string
+	stub bool
+}
+
+// NewTournamentClient returns a TournamentClient for the tournament API, which
+// requires an approved production key. The arguments and options are the same
+// as for New.
+func NewTournamentClient(key string, httpClient external.Doer, limiter ratelimit.Limiter, opts ...Option) TournamentClient {
+	return &tournamentClient{
+		c:   New(key, httpClient, limiter, opts...).(*client),
+		api: "/lol/tournament/v5",
+	}
+}
+
+// NewTournamentStubClient returns a TournamentClient for the tournament stub,
+// which returns mock data and is available to development keys. The arguments
+// and options are the same as for New.
+func NewTournamentStubClient(key string, httpClient external.Doer, limiter ratelimit.Limiter, opts ...Option) TournamentClient {
+	return &tournamentClient{
+		c:    New(key, httpClient, limiter, opts...).(*client),
+		api:  "/lol/tournament-stub/v5",
+		stub: true,
+	}
+}
+
+// send dispatches the method to the Americas regional cluster.
+func (t *tournamentClient) send(ctx context.Context, httpMethod string, m string, relativePath string, v url.Values, u string, body interface{}, dest interface{}) error {
+	r := region.Americas
+	_, err := t.c.sendAndUnmarshal(ctx, httpMethod, t.c.baseURL(string(r), r.Host), string(r), t.api+m, relativePath, v, u, body, dest)
+	return err
+}
+
+func (t *tournamentClient) RegisterProvider(ctx context.Context, r region.Region, callbackURL string) (int, error) {
+	name, ok := tournamentRegions[r]
+	if !ok {
+		return 0, fmt.Errorf("region %s does not support tournaments", r)
+	}
+	params := struct {
+		Region string `json:"region"`
+		URL    string `json:"url"`
+	}{name, callbackURL}
+	var res int
+	err := t.send(ctx, http.MethodPost, "/providers", "", nil, "", &params, &res)
+	return res, err
+}
+
+func (t *tournamentClient) RegisterTournament(ctx context.Context, providerID int, name string) (int64, error) {
+	params := struct {
+		ProviderID int    `json:"providerId"`
+		Name       string `json:"name,omitempty"`
+	}{providerID, name}
+	var res int64
+	err := t.send(ctx, http.MethodPost, "/tournaments", "", nil, "", &params, &res)
+	return res, err
+}
+
+func (t *tournamentClient) CreateTournamentCodes(ctx context.Context, tournamentID int64, count int, params TournamentCodeParameters) ([]string, error) {
+	vals := url.Values{
+		"tournamentId": []string{strconv.FormatInt(tournamentID, 10)},
+		"count":        []string{strconv.Itoa(count)},
+	}
+	var res []string
+	// Creating, reading and updating codes are separate API calls, even though
+	// they have the same Method.
+	err := t.send(ctx, http.MethodPost, "/codes", "", vals, "create", &params, &res)
+	return res, err
+}
+
+func (t *tournamentClient) GetTournamentCode(ctx context.Context, code string) (*TournamentCode, error) {
+	var res TournamentCode
+	err := t.send(ctx, http.MethodGet, "/codes", fmt.Sprintf("/%s", code), nil, "", nil, &res)
+	return &res, err
+}
+
+func (t *tournamentClient) UpdateTournamentCode(ctx context.Context, code string, params TournamentCodeUpdateParameters) error {
+	if t.stub {
+		return ErrNotSupportedByStub
+	}
+	return t.send(ctx, http.MethodPut, "/codes", fmt.Sprintf("/%s", code), nil, "update", &params, nil)
+}
+
+func (t *tournamentClient) GetLobbyEvents(ctx context.Context, code string) ([]LobbyEvent, error) {
+	var res struct {
+		EventList []LobbyEvent `json:"eventList"`
+	}
+	err := t.send(ctx, http.MethodGet, "/lobby-events/by-code", fmt.Sprintf("/%s", code), nil, "", nil, &res)
+	return res.EventList, err
+}
+
+func (t *tournamentClient) GetTournamentGames(ctx context.Context, code string) ([]TournamentGame, error) {
+	if t.stub {
+		return nil, ErrNotSupportedByStub
+	}
+	var res []TournamentGame
+	err := t.send(ctx, http.MethodGet, "/games/by-code", fmt.Sprintf("/%s", code), nil, "", nil, &res)
+	return res, err
+}
+
+// tournamentRegions maps platforms to the region names used by provider
+// registration.
+var tournamentRegions = map[region.Region]string{
+	region.BR1:  "BR",
+	region.EUN1: "EUNE",
+	region.EUW1: "EUW",
+	region.JP1:  "JP",
+	region.KR:   "KR",
+	region.LA1:  "LAN",
+	region.LA2:  "LAS",
+	region.NA1:  "NA",
+	region.OC1:  "OCE",
+	region.TR1:  "TR",
+	region.RU:   "RU",
+}
@@ -0,0 +1,37 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// maxCallbackBytes bounds the size of callback bodies that are parsed.
+const maxCallbackBytes = 1 << 20
+
+// TournamentCallbackHandler returns a handler for the callbacks that Riot
+// POSTs to a registered provider URL when a tournament game completes. The
+// game result is parsed and passed to handle. The handler responds with HTTP
+// OK if handle succeeds, and with HTTP 500 if handle returns an error, so that
+// the failure is visible to Riot. Requests that are not POSTs, or whose body
+// cannot be parsed, are rejected without calling handle.
+func TournamentCallbackHandler(handle func(ctx context.Context, game *TournamentGame) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var game TournamentGame
+		if err := json.NewDecoder(io.LimitReader(req.Body, maxCallbackBytes)).Decode(&game); err != nil {
+			http.Error(w, "malformed game result: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := handle(req.Context(), &game); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
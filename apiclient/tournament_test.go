@@ -0,0 +1,186 @@
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/testing/doertest"
+)
+
+func TestCreateTournamentCodes(t *testing.T) {
+	var (
+		req  *http.Request
+		body []byte
+	)
+	doer := doertest.StaticResponse(http.StatusOK, nil, `["NA1-code-1", "NA1-code-2"]`)
+	c := NewTournamentStubClient("key", doertest.DoerFunc(func(r *http.Request) (*http.Response, error) {
+		req = r
+		body, _ = ioutil.ReadAll(r.Body)
+		return doer(r)
+	}), ratelimit.NewLimiter())
+
+	codes, err := c.CreateTournamentCodes(context.Background(), 42, 2, TournamentCodeParameters{
+		AllowedParticipants: []string{"puuid-1", "puuid-2"},
+		TeamSize:            1,
+		PickType:            PickTournamentDraft,
+		MapType:             MapSummonersRift,
+		SpectatorType:       SpectatorLobbyOnly,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"NA1-code-1", "NA1-code-2"}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("got codes %v, want %v", codes, want)
+	}
+	if req.Method != http.MethodPost {
+		t.Errorf("got HTTP method %s", req.Method)
+	}
+	if got := req.URL.Host + req.URL.Path; got != "americas.api.riotgames.com/lol/tournament-stub/v5/codes" {
+		t.Errorf("got URL %s", got)
+	}
+	if q := req.URL.Query(); q.Get("tournamentId") != "42" || q.Get("count") != "2" {
+		t.Errorf("got query %v", q)
+	}
+	if ct := req.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("got Content-Type %q", ct)
+	}
+	var params map[string]interface{}
+	if err := json.Unmarshal(body, &params); err != nil {
+		t.Fatal(err)
+	}
+	if params["pickType"] != "TOURNAMENT_DRAFT" || params["spectatorType"] != "LOBBYONLY" || params["teamSize"] != 1.0 {
+		t.Errorf("got body %s", body)
+	}
+}
+
+func TestTournamentCallsAreNotRetried(t *testing.T) {
+	policy := testRetryPolicy
+	policy.Statuses = map[int]bool{429: true, 500: true}
+
+	limiter := &countingLimiter{Limiter: ratelimit.NewLimiter()}
+	c := NewTournamentStubClient("key", responseSequence(500, 200), limiter, WithRetryPolicy(policy))
+	if _, err := c.RegisterTournament(context.Background(), 1, "clash"); !errors.Is(err, ErrInternalServerError) {
+		t.Errorf("got error %v, want ErrInternalServerError", err)
+	}
+	if limiter.acquired != 1 {
+		t.Errorf("RegisterTournament made %d attempts, want 1", limiter.acquired)
+	}
+
+	limiter = &countingLimiter{Limiter: ratelimit.NewLimiter()}
+	c = NewTournamentStubClient("key", responseSequence(500, 200), limiter, WithRetryPolicy(policy))
+	if _, err := c.CreateTournamentCodes(context.Background(), 42, 1, TournamentCodeParameters{}); !errors.Is(err, ErrInternalServerError) {
+		t.Errorf("got error %v, want ErrInternalServerError", err)
+	}
+	if limiter.acquired != 1 {
+		t.Errorf("CreateTournamentCodes made %d attempts, want 1", limiter.acquired)
+	}
+
+	// Rate limited calls were not acted on, so they are retried.
+	limiter = &countingLimiter{Limiter: ratelimit.NewLimiter()}
+	limited := doertest.StaticResponse(http.StatusTooManyRequests, nil, "")
+	ok := doertest.StaticResponse(http.StatusOK, nil, "1234")
+	c = NewTournamentStubClient("key", doertest.DoerFunc(func(r *http.Request) (*http.Response, error) {
+		if limiter.acquired == 1 {
+			return limited(r)
+		}
+		return ok(r)
+	}), limiter, WithRetryPolicy(policy))
+	if id, err := c.RegisterTournament(context.Background(), 1, "clash"); err != nil || id != 1234 {
+		t.Fatalf("got tournament %d and error %v, want 1234", id, err)
+	}
+	if limiter.acquired != 2 {
+		t.Errorf("rate limited RegisterTournament made %d attempts, want 2", limiter.acquired)
+	}
+}
+
+func TestUpdateTournamentCode(t *testing.T) {
+	var req *http.Request
+	doer := doertest.StaticResponse(http.StatusOK, nil, "")
+	c := NewTournamentClient("key", doertest.DoerFunc(func(r *http.Request) (*http.Response, error) {
+		req = r
+		return doer(r)
+	}), ratelimit.NewLimiter())
+
+	err := c.UpdateTournamentCode(context.Background(), "NA1-code", TournamentCodeUpdateParameters{PickType: PickBlind})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != http.MethodPut || req.URL.Path != "/lol/tournament/v5/codes/NA1-code" {
+		t.Errorf("got %s %s", req.Method, req.URL.Path)
+	}
+
+	stub := NewTournamentStubClient("key", doer, ratelimit.NewLimiter())
+	if err := stub.UpdateTournamentCode(context.Background(), "NA1-code", TournamentCodeUpdateParameters{}); err != ErrNotSupportedByStub {
+		t.Errorf("got error %v, want %v", err, ErrNotSupportedByStub)
+	}
+}
+
+func TestTournamentCallbackHandler(t *testing.T) {
+	const callback = `{
+	  "startTime": 1234567890000,
+	  "shortCode": "NA1-code",
+	  "metaData": "{\"match\":7}",
+	  "gameId": 1234567890,
+	  "gameName": "game",
+	  "gameType": "Practice",
+	  "gameMap": 11,
+	  "gameMode": "CLASSIC",
+	  "region": "NA1",
+	  "winningTeam": [{"puuid": "puuid-1"}],
+	  "losingTeam": [{"puuid": "puuid-2"}]
+	}`
+
+	var got *TournamentGame
+	h := TournamentCallbackHandler(func(ctx context.Context, game *TournamentGame) error {
+		got = game
+		if game.ShortCode == "fail" {
+			return errors.New("storage unavailable")
+		}
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/callback", strings.NewReader(callback)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d", w.Code)
+	}
+	want := &TournamentGame{
+		StartTime:   1234567890000,
+		ShortCode:   "NA1-code",
+		Metadata:    `{"match":7}`,
+		GameID:      1234567890,
+		GameName:    "game",
+		GameType:    "Practice",
+		GameMap:     11,
+		GameMode:    "CLASSIC",
+		Region:      "NA1",
+		WinningTeam: []TournamentTeam{{PUUID: "puuid-1"}},
+		LosingTeam:  []TournamentTeam{{PUUID: "puuid-2"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got game %+v, want %+v", got, want)
+	}
+
+	for _, test := range []struct {
+		method, body string
+		want         int
+	}{
+		{http.MethodGet, "", http.StatusMethodNotAllowed},
+		{http.MethodPost, "{", http.StatusBadRequest},
+		{http.MethodPost, `{"shortCode": "fail"}`, http.StatusInternalServerError},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(test.method, "/callback", strings.NewReader(test.body)))
+		if w.Code != test.want {
+			t.Errorf("%s %q: got status %d, want %d", test.method, test.body, w.Code, test.want)
+		}
+	}
+}
@@ -14,7 +14,6 @@ import (
 	"github.com/yuhanfang/riot/constants/champion"
 	"github.com/yuhanfang/riot/constants/queue"
 	"github.com/yuhanfang/riot/constants/region"
-	"github.com/yuhanfang/riot/constants/season"
 )
 
 var zeroTime time.Time
@@ -60,7 +59,7 @@ func (c *client) GetChampions(ctx context.Context, r region.Region) (*apiclient.
 	return res, err
 }
 
-func (c *client) GetChampionsByID(ctx context.Context, r region.Region, champ champion.Champion) (*apiclient.Champion, error) {
+func (c *client) GetChampionByID(ctx context.Context, r region.Region, champ champion.Champion) (*apiclient.Champion, error) {
 	var val apiclient.Champion
 	key := fmt.Sprintf("get-champion-by-id:%s:%d", r, champ)
 	t, err := c.d.Get(ctx, key, &val, zeroTime)
@@ -76,6 +75,24 @@ func (c *client) GetChampionsByID(ctx context.Context, r region.Region, champ ch
 	return res, err
 }
 
+func (c *client) GetChampionRotations(ctx context.Context, r region.Region) (*apiclient.ChampionRotation, error) {
+	var val apiclient.ChampionRotation
+	key := fmt.Sprintf("get-champion-rotations:%s", r)
+	t, err := c.d.Get(ctx, key, &val, zeroTime)
+	// The rotation changes weekly, so refresh it more often than other
+	// champion data.
+	if err == nil && time.Since(t) < time.Hour {
+		return &val, nil
+	}
+	res, err := c.Client.GetChampionRotations(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	err = c.d.Put(ctx, key, res, time.Now())
+	go c.d.Purge(ctx, key, 1)
+	return res, err
+}
+
 func (c *client) GetChallengerLeague(ctx context.Context, r region.Region, q queue.Queue) (*apiclient.LeagueList, error) {
 	var val apiclient.LeagueList
 	key := fmt.Sprintf("get-challenger-league:%s:%s", r, q)
@@ -148,85 +165,15 @@ func (c *client) GetMatchTimeline(ctx context.Context, r region.Region, matchID
 	return c.Client.GetMatchTimeline(ctx, r, matchID)
 }
 
-// filterMatchlist applies the given options to the matchlist, returning a new
-// match list that is filtered.
-func filterMatchlist(m *apiclient.Matchlist, opts *apiclient.GetMatchlistOptions) *apiclient.Matchlist {
-	if opts == nil {
-		return m
-	}
-	var res apiclient.Matchlist
-
-	queues := make(map[queue.Queue]bool)
-	for _, q := range opts.Queue {
-		queues[q] = true
-	}
-	seasons := make(map[season.Season]bool)
-	for _, s := range opts.Season {
-		seasons[s] = true
-	}
-	champions := make(map[champion.Champion]bool)
-	for _, c := range opts.Champion {
-		champions[c] = true
-	}
-
-	for i, match := range m.Matches {
-		if len(opts.Queue) > 0 && !queues[match.Queue] {
-			continue
-		}
-		if len(opts.Season) > 0 && !seasons[match.Season] {
-			continue
-		}
-		if len(opts.Champion) > 0 && !champions[match.Champion] {
-			continue
-		}
-
-		ts := match.Timestamp.Time()
-		if opts.BeginTime != nil && opts.EndTime != nil {
-			if opts.BeginTime.After(ts) || opts.EndTime.Before(ts) {
-				continue
-			}
-		} else if opts.EndTime != nil {
-			if opts.EndTime.Before(ts) {
-				continue
-			}
-		} else if opts.BeginTime != nil {
-			if opts.BeginTime.After(ts) {
-				continue
-			}
-		}
-
-		if opts.BeginIndex == nil && opts.EndIndex != nil {
-			beginIndex := 0
-			opts.BeginIndex = &beginIndex
-		} else if opts.BeginIndex != nil && opts.EndIndex == nil {
-			endIndex := *opts.BeginIndex + 100
-			opts.EndIndex = &endIndex
-		}
-
-		if opts.BeginIndex != nil && opts.EndIndex != nil {
-			if i < *opts.BeginIndex || i >= *opts.EndIndex {
-				continue
-			}
-		}
-		res.Matches = append(res.Matches, match)
-	}
-
-	res.TotalGames = len(res.Matches)
-	if opts.BeginIndex != nil {
-		res.StartIndex = *opts.BeginIndex
-	}
-	if opts.EndIndex != nil {
-		res.EndIndex = *opts.EndIndex
-	}
-	return &res
-}
-
+// GetMatchlist caches the full matchlist of the account, and filters it with
+// apiclient.FilterMatchlist. Index options therefore index the filtered
+// matches, as they do in the API.
 func (c *client) GetMatchlist(ctx context.Context, r region.Region, accountID string, opt *apiclient.GetMatchlistOptions) (*apiclient.Matchlist, error) {
 	var val apiclient.Matchlist
 	key := fmt.Sprintf("get-matchlist:%s:%s", r, accountID)
 	t, err := c.d.Get(ctx, key, &val, time.Now())
 	if err == nil && time.Since(t) < 24*time.Hour {
-		return filterMatchlist(&val, opt), nil
+		return apiclient.FilterMatchlist(&val, opt), nil
 	}
 	res, err := c.Client.GetMatchlist(ctx, r, accountID, nil)
 	if err != nil {
@@ -234,7 +181,7 @@ func (c *client) GetMatchlist(ctx context.Context, r region.Region, accountID st
 	}
 	err = c.d.Put(ctx, key, res, time.Now())
 	go c.d.Purge(ctx, key, 1)
-	return filterMatchlist(res, opt), err
+	return apiclient.FilterMatchlist(res, opt), err
 }
 
 func (c *client) GetRecentMatchlist(ctx context.Context, r region.Region, accountID string) (*apiclient.Matchlist, error) {
@@ -0,0 +1,95 @@
+package cachedclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/constants/queue"
+	"github.com/yuhanfang/riot/constants/region"
+)
+
+// memoryDatastore is a Datastore that keeps the latest value of each key.
+type memoryDatastore struct {
+	lock   sync.Mutex
+	values map[string][]byte
+}
+
+func (m *memoryDatastore) Get(ctx context.Context, key string, dest interface{}, t time.Time) (time.Time, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	b, ok := m.values[key]
+	if !ok {
+		return time.Time{}, errors.New("not found")
+	}
+	return time.Now(), json.Unmarshal(b, dest)
+}
+
+func (m *memoryDatastore) Put(ctx context.Context, key string, val interface{}, t time.Time) error {
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.values[key] = b
+	return nil
+}
+
+func (m *memoryDatastore) Purge(ctx context.Context, key string, keep int) error {
+	return nil
+}
+
+// matchlistClient serves a fixed, unfiltered matchlist.
+type matchlistClient struct {
+	apiclient.Client
+
+	matchlist apiclient.Matchlist
+	calls     int
+}
+
+func (c *matchlistClient) GetMatchlist(ctx context.Context, r region.Region, accountID string, opts *apiclient.GetMatchlistOptions) (*apiclient.Matchlist, error) {
+	c.calls++
+	ml := c.matchlist
+	return &ml, nil
+}
+
+func TestGetMatchlistIndexesFilteredMatches(t *testing.T) {
+	api := &matchlistClient{matchlist: apiclient.Matchlist{
+		Matches: []apiclient.MatchReference{
+			{GameID: 1, Queue: queue.RankedSolo5x5},
+			{GameID: 2, Queue: queue.RankedFlexSR},
+			{GameID: 3, Queue: queue.RankedSolo5x5},
+			{GameID: 4, Queue: queue.RankedSolo5x5},
+		},
+	}}
+	c := New(api, &memoryDatastore{values: make(map[string][]byte)})
+
+	begin, end := 1, 3
+	opts := &apiclient.GetMatchlistOptions{
+		Queue:      []queue.Queue{queue.RankedSolo5x5},
+		BeginIndex: &begin,
+		EndIndex:   &end,
+	}
+	// The first call fills the cache, and the second is served from it.
+	for i := 0; i < 2; i++ {
+		ml, err := c.GetMatchlist(context.Background(), region.NA1, "account", opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []int64
+		for _, m := range ml.Matches {
+			ids = append(ids, m.GameID)
+		}
+		if len(ids) != 2 || ids[0] != 3 || ids[1] != 4 {
+			t.Errorf("call %d: got games %v, want [3 4]", i, ids)
+		}
+	}
+	if api.calls != 1 {
+		t.Errorf("got %d API calls, want 1", api.calls)
+	}
+}
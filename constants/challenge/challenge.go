@@ -0,0 +1,64 @@
+// Package challenge defines challenge level constants.
+package challenge
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Level is the level reached in a challenge, or in a category of challenges.
+type Level string
+
+func (l *Level) UnmarshalJSON(b []byte) error {
+	var s string
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+	*l = Level(strings.ToUpper(s))
+	return nil
+}
+
+const (
+	None        Level = "NONE"
+	Iron        Level = "IRON"
+	Bronze      Level = "BRONZE"
+	Silver      Level = "SILVER"
+	Gold        Level = "GOLD"
+	Platinum    Level = "PLATINUM"
+	Diamond     Level = "DIAMOND"
+	Master      Level = "MASTER"
+	Grandmaster Level = "GRANDMASTER"
+	Challenger  Level = "CHALLENGER"
+)
+
+// All returns all levels that can be reached, from lowest to highest.
+func All() []Level {
+	return []Level{
+		Iron,
+		Bronze,
+		Silver,
+		Gold,
+		Platinum,
+		Diamond,
+		Master,
+		Grandmaster,
+		Challenger,
+	}
+}
+
+// Apex returns the levels that have leaderboards, from lowest to highest.
+func Apex() []Level {
+	return []Level{Master, Grandmaster, Challenger}
+}
+
+// Rank returns the position of the level in All(), starting at 1, or 0 for
+// None and unknown levels. Higher levels have higher ranks.
+func (l Level) Rank() int {
+	for i, level := range All() {
+		if l == level {
+			return i + 1
+		}
+	}
+	return 0
+}
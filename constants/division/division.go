@@ -0,0 +1,17 @@
+// Package division defines division constants, which subdivide the tiers
+// below master.
+package division
+
+type Division string
+
+const (
+	I   Division = "I"
+	II  Division = "II"
+	III Division = "III"
+	IV  Division = "IV"
+)
+
+// All returns all divisions, from highest to lowest.
+func All() []Division {
+	return []Division{I, II, III, IV}
+}
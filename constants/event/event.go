@@ -18,3 +18,16 @@ const (
 	CapturePoint           = "CAPTURE_POINT"
 	PoroKingSummon         = "PORO_KING_SUMMON"
 )
+
+// Events introduced by match-v5 timelines.
+const (
+	PauseEnd                Event = "PAUSE_END"
+	GameEnd                       = "GAME_END"
+	LevelUp                       = "LEVEL_UP"
+	ChampionSpecialKill           = "CHAMPION_SPECIAL_KILL"
+	ChampionTransform             = "CHAMPION_TRANSFORM"
+	TurretPlateDestroyed          = "TURRET_PLATE_DESTROYED"
+	DragonSoulGiven               = "DRAGON_SOUL_GIVEN"
+	ObjectiveBountyPrestart       = "OBJECTIVE_BOUNTY_PRESTART"
+	ObjectiveBountyFinish         = "OBJECTIVE_BOUNTY_FINISH"
+)
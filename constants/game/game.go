@@ -0,0 +1,10 @@
+// Package game defines constants for Riot games that have per-game account
+// data, such as active shards.
+package game
+
+type Game string
+
+const (
+	LegendsOfRuneterra Game = "lor"
+	Valorant           Game = "val"
+)
@@ -0,0 +1,12 @@
+// Package matchtype defines match type constants used to filter match-v5
+// matchlists.
+package matchtype
+
+type Type string
+
+const (
+	Ranked   Type = "ranked"
+	Normal   Type = "normal"
+	Tourney  Type = "tourney"
+	Tutorial Type = "tutorial"
+)
@@ -89,3 +89,66 @@ func (r Region) Host() string {
 		panic(fmt.Sprintf("region %s does not have a configured host", r))
 	}
 }
+
+// Routing represents a regional routing value. Some APIs, such as match-v5
+// and account-v1, are served by regional clusters instead of by platforms.
+// Only constants defined in this package are valid inputs for the client.
+type Routing string
+
+const (
+	// Americas serves BR1, LA1, LA2 and NA1.
+	Americas Routing = "AMERICAS"
+
+	// Asia serves JP1 and KR.
+	Asia Routing = "ASIA"
+
+	// Europe serves EUN1, EUW1, TR1 and RU.
+	Europe Routing = "EUROPE"
+
+	// SEA serves OC1.
+	SEA Routing = "SEA"
+)
+
+// AllRoutings returns all supported regional routing values.
+func AllRoutings() []Routing {
+	return []Routing{
+		Americas,
+		Asia,
+		Europe,
+		SEA,
+	}
+}
+
+// Routing returns the regional routing value of the cluster that serves the
+// region. This function panics if an invalid region is used.
+func (r Region) Routing() Routing {
+	switch r {
+	case BR1, LA1, LA2, NA1:
+		return Americas
+	case EUN1, EUW1, TR1, RU:
+		return Europe
+	case JP1, KR:
+		return Asia
+	case OC1:
+		return SEA
+	default:
+		panic(fmt.Sprintf("region %s does not have a configured routing value", r))
+	}
+}
+
+// Host returns the full hostname corresponding to the routing value. This
+// function panics if an invalid routing value is used.
+func (r Routing) Host() string {
+	switch r {
+	case Americas:
+		return "https://americas.api.riotgames.com"
+	case Asia:
+		return "https://asia.api.riotgames.com"
+	case Europe:
+		return "https://europe.api.riotgames.com"
+	case SEA:
+		return "https://sea.api.riotgames.com"
+	default:
+		panic(fmt.Sprintf("routing %s does not have a configured host", r))
+	}
+}
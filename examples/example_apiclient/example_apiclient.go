@@ -10,21 +10,28 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/yuhanfang/riot/apiclient"
 	"github.com/yuhanfang/riot/constants/champion"
+	"github.com/yuhanfang/riot/constants/division"
+	"github.com/yuhanfang/riot/constants/language"
 	"github.com/yuhanfang/riot/constants/queue"
 	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/constants/tier"
 	"github.com/yuhanfang/riot/ratelimit"
 )
 
 const (
 	playerID = "x9k0laU59wtIYnd8zt1dZmtJ_wXl13bqjhTRRC8FPwTbYVA" // These are encrypted per the api key used
 	name     = "waddlechirp"
-	account  = "hJN7Yl1FSZLD4vGKUIAMVFI_IWqK7WmY6Lb9S2QGRSUes8U" // These are encrypted per the api key used
+	riotID   = "waddlechirp#NA1"
+	account  = "hJN7Yl1FSZLD4vGKUIAMVFI_IWqK7WmY6Lb9S2QGRSUes8U"                                // These are encrypted per the api key used
+	puuid    = "fFdJyKkQ0ouGvyF7sfSO8Uk1QEEP1Ms8lUbjpeb1mzcgS9HuMhnJfnNcEv1iCwtF_D8Y0O9W0M5AgA" // These are encrypted per the api key used
 	game     = 2644987649
+	matchID  = "NA1_2644987649"
 	league   = "6b5c7950-5260-11e7-8125-c81f66dbb56c"
-	reg      = region.NA1
+	reg      = region.Region(region.NA1)
 )
 
 func prettyPrint(res interface{}, err error) {
@@ -44,8 +51,43 @@ func main() {
 	key := os.Getenv("RIOT_APIKEY")
 	httpClient := http.DefaultClient
 	ctx := context.Background()
-	limiter := ratelimit.NewLimiter()
-	client := apiclient.New(key, httpClient, limiter)
+	limiter := ratelimit.NewLimiter(ratelimit.WithPreset(ratelimit.DevelopmentKey))
+	client := apiclient.New(key, httpClient, limiter, apiclient.WithRetryPolicy(apiclient.DefaultRetryPolicy))
+
+	// Account
+
+	fmt.Println("GetAccountByPUUID")
+	riotAccount, err := client.GetAccountByPUUID(ctx, reg.Routing(), puuid)
+	prettyPrint(riotAccount, err)
+
+	fmt.Println("GetAccountByRiotID")
+	gameName, tagLine, _ := apiclient.ParseRiotID(riotID)
+	riotAccount, err = client.GetAccountByRiotID(ctx, reg.Routing(), gameName, tagLine)
+	prettyPrint(riotAccount, err)
+
+	fmt.Println("GetSummonerByRiotID")
+	byRiotID, err := apiclient.GetSummonerByRiotID(ctx, client, reg, riotID)
+	prettyPrint(byRiotID, err)
+
+	// Challenges
+
+	fmt.Println("GetPlayerChallenges")
+	challenges, err := client.GetPlayerChallenges(ctx, reg, puuid)
+	prettyPrint(challenges, err)
+	if err == nil {
+		configs, err := client.GetChallengeConfigs(ctx, reg)
+		if err != nil {
+			fmt.Println("HTTP error:", err)
+		}
+		names := make(map[int64]string)
+		for _, c := range configs {
+			names[c.ID] = c.Localization(language.EnglishUnitedStates).Name
+		}
+		fmt.Println("Rarest challenges:")
+		for _, c := range challenges.Rarest(5) {
+			fmt.Printf("%s (%s): top %.2f%%\n", names[c.ChallengeID], c.Level, 100*c.Percentile)
+		}
+	}
 
 	// Champion mastery
 
@@ -61,6 +103,14 @@ func main() {
 	score, err := client.GetChampionMasteryScore(ctx, reg, playerID)
 	prettyPrint(score, err)
 
+	fmt.Println("GetTopChampionMasteries")
+	topMasteries, err := client.GetTopChampionMasteries(ctx, reg, puuid, 5)
+	prettyPrint(topMasteries, err)
+
+	fmt.Println("GetChampionMasteryScoreByPUUID")
+	puuidScore, err := client.GetChampionMasteryScoreByPUUID(ctx, reg, puuid)
+	prettyPrint(puuidScore, err)
+
 	// Champions
 
 	fmt.Println("GetChampions")
@@ -71,6 +121,25 @@ func main() {
 	ashe, err := client.GetChampionByID(ctx, reg, champion.Ashe)
 	prettyPrint(ashe, err)
 
+	fmt.Println("GetChampionRotations")
+	rotation, err := client.GetChampionRotations(ctx, reg)
+	prettyPrint(rotation, err)
+
+	// Clash
+
+	fmt.Println("GetClashPlayersByPUUID")
+	clashPlayers, err := client.GetClashPlayersByPUUID(ctx, reg, puuid)
+	prettyPrint(clashPlayers, err)
+
+	fmt.Println("GetClashTournaments")
+	tournaments, err := client.GetClashTournaments(ctx, reg)
+	prettyPrint(tournaments, err)
+	for _, t := range tournaments {
+		if next := t.NextPhase(time.Now()); next != nil {
+			fmt.Println(t.NameKey, t.NameKeySecondary, "next phase starts", next.StartTime.Time())
+		}
+	}
+
 	// League
 
 	fmt.Println("GetChallengerLeague")
@@ -89,6 +158,27 @@ func main() {
 	myLeague, err := client.GetLeagueByID(ctx, reg, league)
 	prettyPrint(myLeague, err)
 
+	fmt.Println("GetLeagueEntries")
+	entries, err := client.GetLeagueEntries(ctx, reg, queue.RankedSolo5x5, tier.Diamond, division.I, 1)
+	prettyPrint(entries, err)
+
+	fmt.Println("NewLeagueEntriesIterator")
+	it := apiclient.NewLeagueEntriesIterator(client, reg, queue.RankedSolo5x5, tier.Diamond, division.I)
+	// Walk across the first page boundary only, to conserve quota.
+	var numEntries int
+	for numEntries < 300 {
+		_, err := it.Next(ctx)
+		if err == apiclient.ErrIteratorDone {
+			break
+		}
+		if err != nil {
+			prettyPrint(nil, err)
+			break
+		}
+		numEntries++
+	}
+	fmt.Println("Iterated entries:", numEntries)
+
 	// Match
 
 	fmt.Println("GetMatch")
@@ -107,6 +197,28 @@ func main() {
 	recentMatchlist, err := client.GetRecentMatchlist(ctx, reg, account)
 	prettyPrint(recentMatchlist, err)
 
+	// Match-v5
+
+	fmt.Println("GetMatchIDsByPUUID")
+	count := 5
+	matchIDs, err := client.GetMatchIDsByPUUID(ctx, reg, puuid, &apiclient.GetMatchIDsOptions{
+		Count: &count,
+	})
+	prettyPrint(matchIDs, err)
+
+	fmt.Println("GetMatchV5")
+	// Attach a collector to the context to see rate limit usage and timing.
+	metaCtx := apiclient.WithMetadataCollector(ctx, func(m *apiclient.ResponseMetadata) {
+		fmt.Printf("%s %s: HTTP %d in %v after waiting %v for quota; app limits %+v\n",
+			m.Region, m.Method, m.StatusCode, m.Latency, m.RateLimitWait, m.AppLimits)
+	})
+	matchV5, err := client.GetMatchV5(metaCtx, reg, matchID)
+	prettyPrint(matchV5, err)
+
+	fmt.Println("GetMatchTimelineV5")
+	timelineV5, err := client.GetMatchTimelineV5(ctx, reg, matchID)
+	prettyPrint(timelineV5, err)
+
 	// Spectator
 
 	fmt.Println("GetCurrentGameInfoBySummoner")
@@ -117,6 +229,20 @@ func main() {
 	featured, err := client.GetFeaturedGames(ctx, reg)
 	prettyPrint(featured, err)
 
+	fmt.Println("GetCurrentGameInfoByPUUID")
+	currentV5, err := client.GetCurrentGameInfoByPUUID(ctx, reg, puuid)
+	prettyPrint(currentV5, err)
+
+	fmt.Println("GetFeaturedGamesV5")
+	featuredV5, err := client.GetFeaturedGamesV5(ctx, reg)
+	prettyPrint(featuredV5, err)
+
+	// Status
+
+	fmt.Println("GetPlatformStatus")
+	status, err := client.GetPlatformStatus(ctx, reg)
+	prettyPrint(status, err)
+
 	// Summoner
 	fmt.Println("GetByAccountID")
 	summoner, err := client.GetByAccountID(ctx, reg, account)
@@ -130,4 +256,31 @@ func main() {
 	summoner, err = client.GetBySummonerID(ctx, reg, playerID)
 	prettyPrint(summoner, err)
 
+	// Tournament stub, which is available to development keys. Use
+	// NewTournamentClient with a production key to create real codes.
+	tournamentClient := apiclient.NewTournamentStubClient(key, httpClient, limiter)
+
+	fmt.Println("RegisterProvider")
+	providerID, err := tournamentClient.RegisterProvider(ctx, reg, "https://example.com/callback")
+	prettyPrint(providerID, err)
+
+	fmt.Println("RegisterTournament")
+	tournamentID, err := tournamentClient.RegisterTournament(ctx, providerID, "example")
+	prettyPrint(tournamentID, err)
+
+	fmt.Println("CreateTournamentCodes")
+	codes, err := tournamentClient.CreateTournamentCodes(ctx, tournamentID, 1, apiclient.TournamentCodeParameters{
+		AllowedParticipants: []string{puuid},
+		TeamSize:            1,
+		PickType:            apiclient.PickBlind,
+		MapType:             apiclient.MapHowlingAbyss,
+		SpectatorType:       apiclient.SpectatorAll,
+	})
+	prettyPrint(codes, err)
+
+	for _, code := range codes {
+		fmt.Println("GetLobbyEvents")
+		events, err := tournamentClient.GetLobbyEvents(ctx, code)
+		prettyPrint(events, err)
+	}
 }
@@ -0,0 +1,91 @@
+package replay
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, d interface {
+	Do(*http.Request) (*http.Response, error)
+}, url string) (*http.Response, string, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Riot-Token", "secret")
+	res, err := d.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res, string(b), nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var calls int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("X-App-Rate-Limit-Count", fmt.Sprintf("%d:1", calls))
+		fmt.Fprintf(w, "call %d", calls)
+	}))
+	defer ts.Close()
+	dir, err := ioutil.TempDir("", "replay")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := NewRecorder(http.DefaultClient, dir)
+	for i := 0; i < 2; i++ {
+		if _, _, err := get(t, rec, ts.URL+"/lol/foo?b=2&a=1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files, _ := ioutil.ReadDir(dir)
+	for _, f := range files {
+		b, _ := ioutil.ReadFile(dir + "/" + f.Name())
+		if strings.Contains(string(b), "secret") {
+			t.Errorf("fixture %s contains the API key", f.Name())
+		}
+	}
+
+	rep, err := NewReplayer(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Query parameter order does not matter, and the last response repeats.
+	for _, want := range []string{"call 1", "call 2", "call 2"} {
+		res, body, err := get(t, rep, ts.URL+"/lol/foo?a=1&b=2")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body != want {
+			t.Errorf("got body %q, want %q", body, want)
+		}
+		if want == "call 1" && res.Header.Get("X-App-Rate-Limit-Count") != "1:1" {
+			t.Errorf("got rate limit count %q, want 1:1", res.Header.Get("X-App-Rate-Limit-Count"))
+		}
+	}
+	if calls != 2 {
+		t.Errorf("server was called %d times, want 2", calls)
+	}
+
+	if _, _, err := get(t, rep, ts.URL+"/lol/bar"); !errors.Is(err, ErrNoFixture) {
+		t.Errorf("got %v, want ErrNoFixture", err)
+	}
+	rep, err = NewReplayer(dir, http.DefaultClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, body, err := get(t, rep, ts.URL+"/lol/bar"); err != nil || body != "call 3" {
+		t.Errorf("got %q, %v from fallback; want call 3", body, err)
+	}
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time, schedules calls and blocks for a Limiter. The default
+// Clock uses the system time. Tests and simulations can substitute a virtual
+// Clock with WithClock, so that limits are exercised without waiting in real
+// time. A Limiter only ever blocks in Wait, so that a virtual Clock can tell
+// when the callers of the Limiter are blocked.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// AfterFunc calls f once the given duration has passed, and returns a
+	// Timer that can stop the call. The function must not block.
+	AfterFunc(d time.Duration, f func()) Timer
+
+	// Wait blocks until wake is closed or the context is done, and returns
+	// the error of the context in the latter case.
+	Wait(ctx context.Context, wake <-chan struct{}) error
+}
+
+// Timer is a call scheduled by a Clock.
+type Timer interface {
+	// Stop prevents the call from happening. It returns false if the call has
+	// already happened or been stopped.
+	Stop() bool
+}
+
+// SystemClock is the Clock that uses the system time.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+func (systemClock) Wait(ctx context.Context, wake <-chan struct{}) error {
+	select {
+	case <-wake:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Option configures optional behavior of a Limiter returned by NewLimiter.
+type Option func(*limiter)
+
+// WithClock makes the limiter use the given clock instead of SystemClock.
+func WithClock(c Clock) Option {
+	return func(l *limiter) {
+		l.clock = c
+	}
+}
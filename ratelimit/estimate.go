@@ -0,0 +1,100 @@
+package ratelimit
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Estimate is the state of the limits of an invocation at a point in time.
+type Estimate struct {
+	// Time is the time at which the estimate was made.
+	Time time.Time
+
+	// App and Method are the application and method limits of the invocation,
+	// ordered by window. They are empty until the limits are learned from a
+	// response. App is always empty for invocations with NoAppQuota.
+	App    []LimitEstimate
+	Method []LimitEstimate
+
+	// Wake is the earliest time at which the limits, and any Retry-After
+	// received from Riot, allow the invocation, or the zero time if that
+	// depends on calls that are still in flight. It does not account for other
+	// invocations waiting for the same quota.
+	Wake time.Time
+}
+
+// LimitEstimate is the state of a single limit.
+type LimitEstimate struct {
+	Window   time.Duration // Length of the rate limit window.
+	Capacity int64         // Number of calls allowed in the window.
+
+	// Remaining is the number of calls that the invocation may make now. It
+	// excludes the share of the capacity that is reserved for higher
+	// priorities.
+	Remaining int64
+
+	// Wake is the earliest time at which a call is allowed by the limit, or the
+	// zero time if that depends on calls that are still in flight.
+	Wake time.Time
+}
+
+// estimateLocked returns the estimates of the limits of the given quota
+// bucket, and updates the wake time of the estimate. The lock must be held.
+func (l *limiter) estimateLocked(est *Estimate, key Invocation, reserved float64, unknown *bool) []LimitEstimate {
+	l.seedLocked(key)
+	if w := l.methodWake[key]; w.After(est.Wake) {
+		est.Wake = w
+	}
+	il, ok := l.limits[key]
+	if !ok {
+		return nil
+	}
+	var windows []int64
+	for seconds := range il.limits {
+		windows = append(windows, seconds)
+	}
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i] < windows[j]
+	})
+	estimates := make([]LimitEstimate, len(windows))
+	for i, seconds := range windows {
+		lim := il.limits[seconds]
+		estimates[i] = LimitEstimate{
+			Window:    lim.interval,
+			Capacity:  lim.capacity,
+			Remaining: lim.Remaining(est.Time, reserved),
+			Wake:      lim.WakeTime(est.Time, reserved),
+		}
+		if estimates[i].Wake.IsZero() {
+			*unknown = true
+		} else if estimates[i].Wake.After(est.Wake) {
+			est.Wake = estimates[i].Wake
+		}
+	}
+	return estimates
+}
+
+// Estimate reports the current state of the application and method limits of
+// the invocation.
+func (l *limiter) Estimate(ctx context.Context, inv Invocation) (Estimate, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := l.clock.Now()
+	est := Estimate{
+		Time: now,
+		Wake: now,
+	}
+	var (
+		reserved = l.reservedShare(inv.Priority)
+		unknown  bool
+	)
+	est.Method = l.estimateLocked(&est, inv.bucket(), reserved, &unknown)
+	if !inv.NoAppQuota {
+		est.App = l.estimateLocked(&est, inv.App(), reserved, &unknown)
+	}
+	if unknown {
+		est.Wake = time.Time{}
+	}
+	return est, nil
+}
@@ -1,105 +1,191 @@
 package ratelimit
 
 import (
-	"sync"
+	"sort"
 	"time"
 )
 
-// singleLimit is a rate limit corresponding to a specific time interval.
+// singleLimit is a rate limit corresponding to a specific time interval. It
+// keeps a sliding-window log of the times at which acquired units were
+// released, so the time at which quota next becomes available is known
+// exactly, without scheduling a timer per unit. A unit counts against the
+// limit from the time it is acquired until one interval after it is released.
+//
+// singleLimit is not threadsafe. All access is guarded by the lock of the
+// limiter that owns it. Every method that depends on the current time takes
+// it as an argument, as read from the Clock of the limiter.
 type singleLimit struct {
-	lock sync.Mutex
-
-	// riotMatcher is a timer that fires a reconciliation event. For example, if
-	// Riot believes we have used 100 and we believe we have used 90, then we
-	// will immediately up our estimate us usage by 10, and then decrease by 10
-	// after some time has elapsed. Only one reconciliation event is needed at
-	// any given point, since a future reconciliation should immediately replace
-	// the existing one.
-	riotMatcher *time.Timer
-
-	// riotOffset is the quantity that will be added back following the
-	// expiration of riotMatcher.
+	interval time.Duration
+	capacity int64
+
+	// inflight is the number of units that are acquired and not yet released
+	// or cancelled.
+	inflight int64
+
+	// log holds the release times of units that are still within the
+	// interval, oldest first. Entries before head have expired, and are
+	// discarded when the log is compacted.
+	log  []time.Time
+	head int
+
+	// riotOffset reconciles our usage to the counts reported by Riot. For
+	// example, if Riot believes we have used 100 and we believe we have released
+	// 90, then usage is raised by 10 until riotExpiry, which is one interval after
+	// the counts were reported. The offset is negative if Riot reports fewer
+	// counts than we track. Only one reconciliation is needed at any given
+	// point, since a later one replaces the earlier one.
 	riotOffset int64
+	riotExpiry time.Time
 
-	capacity int64
-	quantity int64
+	// successors are the limits that replaced this one while units were in
+	// flight. They count those units as in flight, so releasing or cancelling
+	// a unit of this limit does the same for each successor.
+	successors []*singleLimit
 }
 
-// Acquire attempts to reserve one unit and returns true on success.
-func (s *singleLimit) Acquire() (ok bool) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+// newSingleLimit returns a limit of the given capacity per interval.
+func newSingleLimit(interval time.Duration, capacity int64) *singleLimit {
+	return &singleLimit{
+		interval: interval,
+		capacity: capacity,
+	}
+}
 
-	if s.quantity <= 0 {
-		return false
+// expire drops log entries and reconciliation that are no longer within the
+// interval at the given time.
+func (s *singleLimit) expire(now time.Time) {
+	for s.head < len(s.log) && !s.log[s.head].Add(s.interval).After(now) {
+		s.head++
+	}
+	// Compact once most of the backing array is expired, so that the log only
+	// grows with the number of units in the interval.
+	if s.head > 0 && s.head*2 >= len(s.log) {
+		n := copy(s.log, s.log[s.head:])
+		s.log = s.log[:n]
+		s.head = 0
+	}
+	if s.riotOffset != 0 && !s.riotExpiry.After(now) {
+		s.riotOffset = 0
 	}
-	s.quantity--
-	return true
 }
 
-// Cancel adds one to the available quantity. This must only be called
-// following a successful Acquire(), and is intended to be used to signify that
-// an acquired resource was not used. The function does not check whether this
-// is the case.
-func (s *singleLimit) Cancel() {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-	s.quantity++
+// used returns the number of units that count against the limit. The log must
+// already be expired to the current time.
+func (s *singleLimit) used() int64 {
+	return s.inflight + int64(len(s.log)-s.head) + s.riotOffset
 }
 
-// AddQuantity adds or subtracts the resource after the given duration.
-func (s *singleLimit) AddQuantity(q int64, d time.Duration) {
-	time.AfterFunc(d, func() {
-		s.lock.Lock()
-		defer s.lock.Unlock()
-		s.quantity += q
-	})
+// usable returns the capacity that is usable by an invocation that must leave
+// the given share of the capacity free.
+func (s *singleLimit) usable(reserved float64) int64 {
+	return s.capacity - int64(float64(s.capacity)*reserved)
 }
 
-// SetCapacity sets the new limit capacity. The available resources are shrunk
-// to the new capacity if it is smaller. The resources are increased by the
-// capacity increase if the new capacity is larger.
-func (s *singleLimit) SetCapacity(c int64) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-	old := s.capacity
-	s.capacity = c
-	if c > old {
-		s.quantity += (c - old)
+// Available returns true if a unit can be acquired at the given time, while
+// leaving the given share of the capacity free.
+func (s *singleLimit) Available(now time.Time, reserved float64) bool {
+	s.expire(now)
+	return s.used() < s.usable(reserved)
+}
+
+// Remaining returns the number of units that can be acquired at the given time,
+// while leaving the given share of the capacity free.
+func (s *singleLimit) Remaining(now time.Time, reserved float64) int64 {
+	s.expire(now)
+	if r := s.usable(reserved) - s.used(); r > 0 {
+		return r
+	}
+	return 0
+}
+
+// Acquire reserves one unit. It must only be called after Available returns
+// true, and does not check whether this is the case.
+func (s *singleLimit) Acquire() {
+	s.inflight++
+}
+
+// Cancel releases an acquired unit immediately. This must only be called
+// following Acquire(), and is intended to be used to signify that an acquired
+// resource was not used. The function does not check whether this is the case.
+func (s *singleLimit) Cancel() {
+	s.inflight--
+	for _, next := range s.successors {
+		next.Cancel()
 	}
-	if s.quantity > c {
-		s.quantity = c
+}
+
+// Release records that an acquired unit was used at the given time. The unit
+// continues to count against the limit for one interval.
+func (s *singleLimit) Release(now time.Time) {
+	s.inflight--
+	s.log = append(s.log, now)
+	for _, next := range s.successors {
+		next.Release(now)
 	}
 }
 
-// MatchRiotCounts reconciles the currently tracked quantity to the given
-// counts from Riot. If the Riot counts are higher than the implied counts we
-// are tracking, we will immediately lower the current quantity by the
-// difference, and add an eventual offest after the given duration.
-func (s *singleLimit) MatchRiotCounts(counts int64, reverseAfter time.Duration) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
-
-	if s.riotMatcher != nil {
-		// If the reconciliation already happened, there's no need for special
-		// logic, since the reconciliation we are about to schedule can be added
-		// right on top. If the reconciliation was pending, then we need to reverse
-		// it first.
-		if stopped := s.riotMatcher.Stop(); stopped {
-			s.quantity += s.riotOffset
+// WakeTime returns the earliest time at which a unit will be available while
+// leaving the given share of the capacity free, if no units are acquired or
+// released in the meantime. It returns the given time if a unit is available
+// now, and the zero time if units only become available once in-flight units
+// are released.
+func (s *singleLimit) WakeTime(now time.Time, reserved float64) time.Time {
+	s.expire(now)
+	deficit := s.used() - s.usable(reserved) + 1
+	if deficit <= 0 {
+		return now
+	}
+
+	log := s.log[s.head:]
+	// expiry returns the time at which the first n log entries have expired.
+	expiry := func(n int64) time.Time {
+		return log[n-1].Add(s.interval)
+	}
+	if s.riotOffset == 0 {
+		if deficit <= int64(len(log)) {
+			return expiry(deficit)
 		}
+		return time.Time{}
 	}
 
-	// Example: capacity of 10, Riot shows 4 counts => 6 implied quantity
-	impliedQuantity := s.capacity - counts
-
-	if impliedQuantity != s.quantity {
-		s.riotOffset = s.quantity - impliedQuantity
-		s.quantity -= s.riotOffset
-		s.riotMatcher = time.AfterFunc(reverseAfter, func() {
-			s.lock.Lock()
-			defer s.lock.Unlock()
-			s.quantity += s.riotOffset
-		})
+	// Log entries that expire before the reconciliation are the only units
+	// freed until then. Afterwards, the reconciliation itself frees riotOffset
+	// units, or takes them back if it is negative, in which case the entries
+	// that expire along with it free nothing.
+	beforeRiot := int64(sort.Search(len(log), func(i int) bool {
+		t := log[i].Add(s.interval)
+		return t.After(s.riotExpiry) || s.riotOffset < 0 && t.Equal(s.riotExpiry)
+	}))
+	if deficit <= beforeRiot {
+		return expiry(deficit)
+	}
+	afterRiot := deficit - s.riotOffset
+	switch {
+	case afterRiot <= beforeRiot:
+		return s.riotExpiry
+	case afterRiot <= int64(len(log)):
+		return expiry(afterRiot)
+	default:
+		return time.Time{}
 	}
 }
+
+// SetCapacity sets the new limit capacity. Units that are already in use keep
+// counting against the new capacity.
+func (s *singleLimit) SetCapacity(c int64) {
+	s.capacity = c
+}
+
+// MatchRiotCounts reconciles the tracked usage to the given counts from Riot
+// at the given time. The usage of released units is moved to the Riot counts,
+// up or down, until one interval has passed, after which the difference is
+// reversed. A later reconciliation replaces a pending one.
+//
+// Units in flight keep counting, since Riot counts a call when it arrives, and
+// so has not necessarily counted the calls that are still in flight.
+func (s *singleLimit) MatchRiotCounts(counts int64, now time.Time) {
+	s.expire(now)
+	s.riotOffset = 0
+	s.riotOffset = counts - (s.used() - s.inflight)
+	s.riotExpiry = now.Add(s.interval)
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSingleLimitWakeTime(t *testing.T) {
+	start := time.Unix(1000, 0)
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+	s := newSingleLimit(time.Second, 3)
+
+	for i := 0; i < 3; i++ {
+		if !s.Available(at(0), 0) {
+			t.Fatalf("unit %d is not available", i)
+		}
+		s.Acquire()
+	}
+	if got := s.WakeTime(at(0), 0); !got.IsZero() {
+		t.Errorf("got wake time %v with all units in flight, want zero", got)
+	}
+
+	s.Release(at(100))
+	s.Release(at(200))
+	s.Cancel()
+	if !s.Available(at(200), 0) {
+		t.Fatal("cancelled unit is not available")
+	}
+	s.Acquire()
+	if got, want := s.WakeTime(at(300), 0), at(1100); !got.Equal(want) {
+		t.Errorf("got wake time %v, want %v", got, want)
+	}
+	if s.Available(at(1099), 0) || !s.Available(at(1100), 0) {
+		t.Error("released unit did not expire after one interval")
+	}
+
+	// Riot reports one more unit than we released, until one interval later.
+	s.MatchRiotCounts(2, at(1100))
+	if got, want := s.WakeTime(at(1100), 0), at(1200); !got.Equal(want) {
+		t.Errorf("got wake time %v after reconciliation, want %v", got, want)
+	}
+	s.MatchRiotCounts(3, at(1150))
+	if got, want := s.WakeTime(at(1150), 0), at(2150); !got.Equal(want) {
+		t.Errorf("got wake time %v after second reconciliation, want %v", got, want)
+	}
+}
+
+func TestSingleLimitMatchesLowerRiotCounts(t *testing.T) {
+	start := time.Unix(1000, 0)
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+	s := newSingleLimit(time.Second, 1)
+	s.Acquire()
+	s.Release(at(0))
+
+	// Riot has not counted the call, which frees its unit until one interval
+	// later.
+	s.MatchRiotCounts(0, at(0))
+	if !s.Available(at(0), 0) {
+		t.Fatal("lower Riot count did not free a unit")
+	}
+	s.Acquire()
+	s.Release(at(500))
+
+	// The reconciliation is reversed as the first call expires, so both calls
+	// count until the second one expires.
+	if got, want := s.WakeTime(at(500), 0), at(1500); !got.Equal(want) {
+		t.Errorf("got wake time %v, want %v", got, want)
+	}
+	if s.Available(at(1000), 0) || !s.Available(at(1500), 0) {
+		t.Error("reconciliation was not reversed after one interval")
+	}
+}
+
+func TestSingleLimitKeepsUnitsInFlight(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := newSingleLimit(time.Second, 3)
+	s.Acquire()
+	s.Acquire()
+	s.Release(now)
+
+	// Riot has not counted the unit that is still in flight yet.
+	s.MatchRiotCounts(1, now)
+	s.Acquire()
+	if s.Available(now, 0) {
+		t.Error("lower Riot count freed an in-flight unit")
+	}
+	s.Release(now)
+	s.Release(now)
+
+	// Once the calls are released, Riot counts them.
+	s.MatchRiotCounts(2, now)
+	if !s.Available(now, 0) {
+		t.Error("Riot count did not lower the usage of released units")
+	}
+}
+
+func TestSingleLimitCompacts(t *testing.T) {
+	start := time.Unix(1000, 0)
+	s := newSingleLimit(time.Second, 10)
+	for i := 0; i < 10000; i++ {
+		now := start.Add(time.Duration(i) * 200 * time.Millisecond)
+		if !s.Available(now, 0) {
+			t.Fatalf("unit %d is not available", i)
+		}
+		s.Acquire()
+		s.Release(now)
+	}
+	if len(s.log) > 10 {
+		t.Errorf("log holds %d entries, want at most 10", len(s.log))
+	}
+}
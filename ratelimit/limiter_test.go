@@ -0,0 +1,626 @@
+package ratelimit_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/ratelimit/simulation"
+)
+
+func TestLimiterWaitsForWindow(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := simulation.NewClock(start)
+	l := ratelimit.NewLimiter(ratelimit.WithClock(clock))
+	ctx := context.Background()
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+
+	done, _, err := l.Acquire(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := make(http.Header)
+	header.Set("X-App-Rate-Limit", "100:1")
+	header.Set("X-App-Rate-Limit-Count", "1:1")
+	header.Set("X-Method-Rate-Limit", "1:1")
+	header.Set("X-Method-Rate-Limit-Count", "1:1")
+	if err := done(&http.Response{Header: header}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The method limit is exhausted until one second after the release.
+	acquired := make(chan time.Time, 1)
+	clock.Go(ctx, func(ctx context.Context) {
+		if _, _, err := l.Acquire(ctx, inv); err != nil {
+			t.Error(err)
+		}
+		acquired <- clock.Now()
+	})
+	clock.Settle()
+	clock.Advance(999 * time.Millisecond)
+	select {
+	case at := <-acquired:
+		t.Fatalf("acquired at %v with no quota", at.Sub(start))
+	default:
+	}
+	clock.Advance(time.Millisecond)
+	if got, want := (<-acquired).Sub(start), time.Second; got != want {
+		t.Errorf("acquired after %v, want %v", got, want)
+	}
+
+	// Other methods only share the application limit.
+	other := inv
+	other.Method = "/lol/match/v5/matches"
+	if _, _, err := l.Acquire(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimiterRetryAfter(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := simulation.NewClock(start)
+	l := ratelimit.NewLimiter(ratelimit.WithClock(clock))
+	ctx := context.Background()
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+
+	done, _, err := l.Acquire(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := make(http.Header)
+	header.Set("Retry-After", "1")
+	header.Set("X-Rate-Limit-Type", "method")
+	if err := done(&http.Response{StatusCode: 429, Header: header}); err != nil {
+		t.Fatal(err)
+	}
+
+	other := inv
+	other.Method = "/lol/match/v5/matches"
+	if _, _, err := l.Acquire(ctx, other); err != nil {
+		t.Errorf("method Retry-After blocked another method: %v", err)
+	}
+
+	acquired := make(chan time.Time, 1)
+	clock.Go(ctx, func(ctx context.Context) {
+		if _, _, err := l.Acquire(ctx, inv); err != nil {
+			t.Error(err)
+		}
+		acquired <- clock.Now()
+	})
+	clock.Settle()
+	clock.Advance(time.Second)
+	if got, want := (<-acquired).Sub(start), time.Second; got != want {
+		t.Errorf("acquired after %v, want %v", got, want)
+	}
+}
+
+func TestLimiterCancelWakesWaiter(t *testing.T) {
+	clock := simulation.NewClock(time.Unix(1000, 0))
+	l := ratelimit.NewLimiter(ratelimit.WithClock(clock))
+	ctx := context.Background()
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+	learnLimits(t, l, inv, "1:3600", "0:3600")
+
+	_, cancel, err := l.Acquire(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acquired := make(chan error, 1)
+	clock.Go(ctx, func(ctx context.Context) {
+		_, _, err := l.Acquire(ctx, inv)
+		acquired <- err
+	})
+	clock.Settle()
+	select {
+	case err := <-acquired:
+		t.Fatalf("acquired with no quota: %v", err)
+	default:
+	}
+
+	// Cancelling the call in flight returns its quota without time passing.
+	cancel()
+	clock.Settle()
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatal(err)
+		}
+	default:
+		t.Fatal("cancel did not wake the waiting call")
+	}
+}
+
+// learnLimits acquires and completes a call to inv with the given method limit
+// header, so that the limiter knows the limit.
+func learnLimits(t *testing.T, l ratelimit.Limiter, inv ratelimit.Invocation, limit, count string) {
+	done, _, err := l.Acquire(context.Background(), inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := make(http.Header)
+	header.Set("X-Method-Rate-Limit", limit)
+	header.Set("X-Method-Rate-Limit-Count", count)
+	if err := done(&http.Response{Header: header}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimiterSchedulesOneTimer(t *testing.T) {
+	clock := simulation.NewClock(time.Unix(1000, 0))
+	l := ratelimit.NewLimiter(ratelimit.WithClock(clock))
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+	learnLimits(t, l, inv, "1:10", "1:10")
+
+	// However many calls wait for quota, the limiter schedules a single timer,
+	// and only wakes the call that it grants quota to.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer stopWaiters(t, clock, cancel)
+	acquired := make(chan struct{}, 100)
+	for i := 0; i < 100; i++ {
+		clock.Go(ctx, func(ctx context.Context) {
+			done, _, err := l.Acquire(ctx, inv)
+			if err != nil {
+				return
+			}
+			done(nil)
+			acquired <- struct{}{}
+		})
+	}
+	clock.Settle()
+	for i := 1; i <= 3; i++ {
+		if got := clock.Pending(); got != 1 {
+			t.Errorf("got %d pending timers, want 1", got)
+		}
+		clock.Advance(10 * time.Second)
+		if got := len(acquired); got != i {
+			t.Errorf("got %d calls after %d windows, want %d", got, i, i)
+		}
+	}
+
+}
+
+// stopWaiters cancels the context of the calls still waiting on the clock, and
+// fails the test unless they all return.
+func stopWaiters(t *testing.T, clock *simulation.Clock, cancel context.CancelFunc) {
+	cancel()
+	clock.Settle()
+	if n := clock.Goroutines(); n != 0 {
+		t.Errorf("%d goroutines still running", n)
+	}
+}
+
+func TestLimiterServesByPriority(t *testing.T) {
+	clock := simulation.NewClock(time.Unix(1000, 0))
+	l := ratelimit.NewLimiter(ratelimit.WithClock(clock))
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+	learnLimits(t, l, inv, "1:10", "1:10")
+
+	// Waiters are queued in the order in which they call Acquire, and each
+	// completes its call as soon as it acquires quota. Settling the clock
+	// returns once the waiter is queued and blocked, before the next one
+	// starts.
+	order := make(chan string, 5)
+	for _, w := range []struct {
+		name     string
+		priority ratelimit.Priority
+	}{
+		{"background", ratelimit.PriorityBackground},
+		{"normal 1", ratelimit.PriorityNormal},
+		{"interactive", ratelimit.PriorityInteractive},
+		{"normal 2", ratelimit.PriorityNormal},
+		{"normal 3", ratelimit.PriorityNormal},
+	} {
+		inv := inv
+		inv.Priority = w.priority
+		name := w.name
+		clock.Go(context.Background(), func(ctx context.Context) {
+			done, _, err := l.Acquire(ctx, inv)
+			if err != nil {
+				t.Error(err)
+			}
+			order <- name
+			done(nil)
+		})
+		clock.Settle()
+	}
+
+	for _, want := range []string{"interactive", "normal 1", "normal 2", "normal 3", "background"} {
+		clock.Advance(10 * time.Second)
+		select {
+		case got := <-order:
+			if got != want {
+				t.Errorf("served %q, want %q", got, want)
+			}
+		default:
+			t.Fatalf("nothing served, want %q", want)
+		}
+	}
+}
+
+func TestLimiterPriorityIsSharedQuota(t *testing.T) {
+	clock := simulation.NewClock(time.Unix(1000, 0))
+	l := ratelimit.NewLimiter(ratelimit.WithClock(clock))
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+	learnLimits(t, l, inv, "1:10", "1:10")
+
+	// Limits learned at one priority apply to all priorities.
+	inv.Priority = ratelimit.PriorityInteractive
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	clock.Go(ctx, func(ctx context.Context) {
+		_, _, err := l.Acquire(ctx, inv)
+		errs <- err
+	})
+	clock.Settle()
+	cancel()
+	if err := <-errs; err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestLimiterReservedShare(t *testing.T) {
+	clock := simulation.NewClock(time.Unix(1000, 0))
+	l := ratelimit.NewLimiter(
+		ratelimit.WithClock(clock),
+		ratelimit.WithReservedShare(ratelimit.PriorityInteractive, 0.5),
+	)
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+	learnLimits(t, l, inv, "4:10", "1:10")
+
+	// Normal calls may only use half of the limit.
+	ctx := context.Background()
+	done, _, err := l.Acquire(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	done(nil)
+	normal := make(chan error, 1)
+	clock.Go(ctx, func(ctx context.Context) {
+		_, _, err := l.Acquire(ctx, inv)
+		normal <- err
+	})
+	clock.Settle()
+
+	// Interactive calls may use all of it, even while normal calls wait.
+	interactive := inv
+	interactive.Priority = ratelimit.PriorityInteractive
+	for i := 0; i < 2; i++ {
+		done, _, err := l.Acquire(ctx, interactive)
+		if err != nil {
+			t.Fatal(err)
+		}
+		done(nil)
+	}
+	select {
+	case err := <-normal:
+		t.Fatalf("normal call used reserved quota: %v", err)
+	default:
+	}
+
+	// Once the first calls expire, the normal call fits in its share.
+	clock.Advance(10 * time.Second)
+	if err := <-normal; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimiterDoesNotBlockOtherMethods(t *testing.T) {
+	clock := simulation.NewClock(time.Unix(1000, 0))
+	l := ratelimit.NewLimiter(ratelimit.WithClock(clock))
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+	learnLimits(t, l, inv, "1:10", "1:10")
+
+	// An interactive call waiting for its method quota does not hold back
+	// calls to other methods that share the application quota.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer stopWaiters(t, clock, cancel)
+	clock.Go(ctx, func(ctx context.Context) {
+		inv := inv
+		inv.Priority = ratelimit.PriorityInteractive
+		l.Acquire(ctx, inv)
+	})
+	clock.Settle()
+	other := inv
+	other.Method = "/lol/match/v5/matches"
+	other.Priority = ratelimit.PriorityBackground
+	if _, _, err := l.Acquire(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimiterTryAcquire(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := simulation.NewClock(start)
+	l := ratelimit.NewLimiter(ratelimit.WithClock(clock))
+	ctx := context.Background()
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+	learnLimits(t, l, inv, "2:10", "1:10")
+
+	_, cancel, ok, _, err := l.TryAcquire(ctx, inv)
+	if err != nil || !ok {
+		t.Fatalf("got ok %v and error %v, want success", ok, err)
+	}
+	if _, _, ok, wake, err := l.TryAcquire(ctx, inv); err != nil || ok || !wake.Equal(start.Add(10*time.Second)) {
+		t.Errorf("got ok %v, wake %v and error %v, want wake after 10s", ok, wake.Sub(start), err)
+	}
+
+	// Once the only released call expires, quota depends on the call in
+	// flight.
+	clock.Advance(10 * time.Second)
+	if _, _, ok, _, _ := l.TryAcquire(ctx, inv); !ok {
+		t.Fatal("did not acquire expired quota")
+	}
+	if _, _, ok, wake, err := l.TryAcquire(ctx, inv); err != nil || ok || !wake.IsZero() {
+		t.Errorf("got ok %v, wake %v and error %v with all calls in flight, want zero wake", ok, wake, err)
+	}
+	cancel()
+
+	// A call held back by a waiting interactive call wakes no earlier than
+	// that call.
+	clock = simulation.NewClock(start)
+	l = ratelimit.NewLimiter(ratelimit.WithClock(clock))
+	learnLimits(t, l, inv, "1:10", "1:10")
+	waitCtx, stop := context.WithCancel(ctx)
+	defer stopWaiters(t, clock, stop)
+	clock.Go(waitCtx, func(ctx context.Context) {
+		inv := inv
+		inv.Priority = ratelimit.PriorityInteractive
+		l.Acquire(ctx, inv)
+	})
+	clock.Settle()
+	est, err := l.Estimate(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, ok, wake, err := l.TryAcquire(ctx, inv); err != nil || ok || !wake.Equal(start.Add(10*time.Second)) || !wake.Equal(est.Wake) {
+		t.Errorf("got ok %v, wake %v and error %v behind a waiting call, want wake after 10s like the estimate %v", ok, wake.Sub(start), err, est.Wake.Sub(start))
+	}
+}
+
+func TestLimiterTryAcquireWaitsItsTurn(t *testing.T) {
+	clock := simulation.NewClock(time.Unix(1000, 0))
+	l := ratelimit.NewLimiter(
+		ratelimit.WithClock(clock),
+		ratelimit.WithReservedShare(ratelimit.PriorityNormal, 0.5),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer stopWaiters(t, clock, cancel)
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+	learnLimits(t, l, inv, "2:10", "1:10")
+
+	// A background call waits for the share it may use, which does not hold
+	// back normal calls.
+	clock.Go(ctx, func(ctx context.Context) {
+		inv := inv
+		inv.Priority = ratelimit.PriorityBackground
+		l.Acquire(ctx, inv)
+	})
+	clock.Settle()
+	if _, _, ok, _, _ := l.TryAcquire(ctx, inv); !ok {
+		t.Fatal("normal call did not overtake a waiting background call")
+	}
+
+	// Quota that becomes available goes to a waiting call of the same
+	// priority before TryAcquire.
+	acquired := make(chan error, 1)
+	clock.Go(ctx, func(ctx context.Context) {
+		_, _, err := l.Acquire(ctx, inv)
+		acquired <- err
+	})
+	clock.Settle()
+	clock.Advance(10 * time.Second)
+	if _, _, ok, _, _ := l.TryAcquire(ctx, inv); ok {
+		t.Error("normal call overtook a waiting normal call")
+	}
+	if err := <-acquired; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLimiterEstimate(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := simulation.NewClock(start)
+	l := ratelimit.NewLimiter(
+		ratelimit.WithClock(clock),
+		ratelimit.WithReservedShare(ratelimit.PriorityInteractive, 0.5),
+	)
+	ctx := context.Background()
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+
+	est, err := l.Estimate(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(est.App) != 0 || len(est.Method) != 0 || !est.Wake.Equal(start) {
+		t.Errorf("got estimate %+v before any response, want no limits", est)
+	}
+
+	done, _, err := l.Acquire(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := make(http.Header)
+	header.Set("X-App-Rate-Limit", "100:120,20:1")
+	header.Set("X-App-Rate-Limit-Count", "1:120,1:1")
+	header.Set("X-Method-Rate-Limit", "2:10")
+	header.Set("X-Method-Rate-Limit-Count", "1:10")
+	if err := done(&http.Response{Header: header}); err != nil {
+		t.Fatal(err)
+	}
+
+	est, err = l.Estimate(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantApp := []ratelimit.LimitEstimate{
+		{Window: time.Second, Capacity: 20, Remaining: 9, Wake: start},
+		{Window: 2 * time.Minute, Capacity: 100, Remaining: 49, Wake: start},
+	}
+	if len(est.App) != len(wantApp) {
+		t.Fatalf("got app estimates %+v, want %+v", est.App, wantApp)
+	}
+	for i := range wantApp {
+		if est.App[i] != wantApp[i] {
+			t.Errorf("got app estimate %+v, want %+v", est.App[i], wantApp[i])
+		}
+	}
+	wantMethod := ratelimit.LimitEstimate{Window: 10 * time.Second, Capacity: 2, Remaining: 0, Wake: start.Add(10 * time.Second)}
+	if len(est.Method) != 1 || est.Method[0] != wantMethod {
+		t.Errorf("got method estimates %+v, want %+v", est.Method, wantMethod)
+	}
+	if !est.Wake.Equal(wantMethod.Wake) {
+		t.Errorf("got wake %v, want %v", est.Wake.Sub(start), wantMethod.Wake.Sub(start))
+	}
+
+	// Interactive calls may use the reserved share.
+	inv.Priority = ratelimit.PriorityInteractive
+	est, err = l.Estimate(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(est.Method) != 1 || est.Method[0].Remaining != 1 || !est.Wake.Equal(start) {
+		t.Errorf("got interactive estimate %+v, want 1 remaining call now", est)
+	}
+}
+
+func TestLimiterPreset(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := simulation.NewClock(start)
+	l := ratelimit.NewLimiter(ratelimit.WithClock(clock), ratelimit.WithPreset(ratelimit.ProductionKey))
+	ctx := context.Background()
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/league/v4/challengerleagues/by-queue"}
+
+	est, err := l.Estimate(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(est.App) != 2 || est.App[0].Capacity != 500 || est.App[1].Capacity != 30000 {
+		t.Errorf("got app estimates %+v, want the production key limits", est.App)
+	}
+	if len(est.Method) != 2 || est.Method[0].Capacity != 30 || est.Method[1].Capacity != 500 {
+		t.Errorf("got method estimates %+v, want the known method limits", est.Method)
+	}
+
+	// Learned limits replace the preset.
+	done, _, err := l.Acquire(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := make(http.Header)
+	header.Set("X-Method-Rate-Limit", "60:10")
+	header.Set("X-Method-Rate-Limit-Count", "1:10")
+	if err := done(&http.Response{Header: header}); err != nil {
+		t.Fatal(err)
+	}
+	est, err = l.Estimate(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ratelimit.LimitEstimate{Window: 10 * time.Second, Capacity: 60, Remaining: 59, Wake: start}
+	if len(est.Method) != 1 || est.Method[0] != want {
+		t.Errorf("got method estimates %+v, want %+v", est.Method, want)
+	}
+}
+
+func TestLimiterLearnedLimitsCountCallsInFlight(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := simulation.NewClock(start)
+	l := ratelimit.NewLimiter(ratelimit.WithClock(clock), ratelimit.WithPreset(ratelimit.ProductionKey))
+	ctx := context.Background()
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/league/v4/challengerleagues/by-queue"}
+
+	first, _, err := l.Acquire(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _, err := l.Acquire(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The learned limit replaces both windows of the preset, and still counts
+	// the second call once the count reported by Riot expires.
+	header := make(http.Header)
+	header.Set("X-Method-Rate-Limit", "2:5")
+	header.Set("X-Method-Rate-Limit-Count", "1:5")
+	if err := first(&http.Response{Header: header}); err != nil {
+		t.Fatal(err)
+	}
+	remaining := func() int64 {
+		est, err := l.Estimate(ctx, inv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(est.Method) != 1 {
+			t.Fatalf("got method estimates %+v, want one learned limit", est.Method)
+		}
+		return est.Method[0].Remaining
+	}
+	clock.Advance(5 * time.Second)
+	if got, want := remaining(), int64(1); got != want {
+		t.Errorf("got %d remaining with a call in flight, want %d", got, want)
+	}
+
+	// Releasing the call releases it from the learned limit.
+	if err := second(nil); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(5 * time.Second)
+	if got, want := remaining(), int64(2); got != want {
+		t.Errorf("got %d remaining once the call expired, want %d", got, want)
+	}
+}
+
+func TestPresetsDoNotShareMethodLimits(t *testing.T) {
+	const method = "/lol/match/v5/matches"
+	saved := ratelimit.DevelopmentKey.Methods[method]
+	defer func() {
+		ratelimit.DevelopmentKey.Methods[method] = saved
+	}()
+	ratelimit.DevelopmentKey.Methods[method] = []ratelimit.Limit{{1, time.Second}}
+
+	for name, methods := range map[string]map[string][]ratelimit.Limit{
+		"MethodLimits":  ratelimit.MethodLimits,
+		"PersonalKey":   ratelimit.PersonalKey.Methods,
+		"ProductionKey": ratelimit.ProductionKey.Methods,
+	} {
+		if got := methods[method]; len(got) != 1 || got[0].Requests != 2000 {
+			t.Errorf("%s has limits %v after changing the development key", name, got)
+		}
+	}
+}
+
+func TestLimiterConservativeStart(t *testing.T) {
+	clock := simulation.NewClock(time.Unix(1000, 0))
+	l := ratelimit.NewLimiter(ratelimit.WithClock(clock), ratelimit.WithConservativeStart())
+	ctx := context.Background()
+	inv := ratelimit.Invocation{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners"}
+
+	done, _, ok, _, err := l.TryAcquire(ctx, inv)
+	if err != nil || !ok {
+		t.Fatalf("got ok %v and error %v, want success", ok, err)
+	}
+	if _, _, ok, _, _ := l.TryAcquire(ctx, inv); ok {
+		t.Fatal("acquired a second call before limits are known")
+	}
+	header := make(http.Header)
+	header.Set("X-App-Rate-Limit", "20:1")
+	header.Set("X-App-Rate-Limit-Count", "1:1")
+	header.Set("X-Method-Rate-Limit", "10:1")
+	header.Set("X-Method-Rate-Limit-Count", "1:1")
+	if err := done(&http.Response{Header: header}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once limits are known, calls are made concurrently.
+	for i := 0; i < 9; i++ {
+		if _, _, ok, _, _ := l.TryAcquire(ctx, inv); !ok {
+			t.Fatalf("call %d did not acquire quota", i)
+		}
+	}
+	if _, _, ok, _, _ := l.TryAcquire(ctx, inv); ok {
+		t.Error("acquired more than the method limit")
+	}
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import "time"
+
+// Limit is a rate limit of a number of requests per time window.
+type Limit struct {
+	Requests int64
+	Window   time.Duration
+}
+
+// Preset holds the limits that a limiter assumes before it learns the actual
+// limits from the headers of a response. Limits that Riot reports always
+// replace those of the preset.
+type Preset struct {
+	// App are the application limits, which apply separately to each region.
+	App []Limit
+
+	// Methods maps Invocation.Method to the limits of the method. The limits
+	// apply separately to each region and uniquifier.
+	Methods map[string][]Limit
+}
+
+// MethodLimits are the method limits of known endpoints, as documented by Riot
+// for both development and production keys.
+var MethodLimits = map[string][]Limit{
+	"/lol/champion-mastery/v4/champion-masteries/by-puuid":    {{20000, 10 * time.Second}, {1200000, 10 * time.Minute}},
+	"/lol/champion-mastery/v4/champion-masteries/by-summoner": {{20000, 10 * time.Second}, {1200000, 10 * time.Minute}},
+	"/lol/champion-mastery/v4/scores/by-puuid":                {{20000, 10 * time.Second}, {1200000, 10 * time.Minute}},
+	"/lol/champion-mastery/v4/scores/by-summoner":             {{20000, 10 * time.Second}, {1200000, 10 * time.Minute}},
+	"/lol/league/v4/challengerleagues/by-queue":               {{30, 10 * time.Second}, {500, 10 * time.Minute}},
+	"/lol/league/v4/entries":                                  {{50, 10 * time.Second}},
+	"/lol/league/v4/entries/by-summoner":                      {{100, time.Minute}},
+	"/lol/league/v4/grandmasterleagues/by-queue":              {{30, 10 * time.Second}, {500, 10 * time.Minute}},
+	"/lol/league/v4/leagues":                                  {{500, 10 * time.Second}},
+	"/lol/league/v4/masterleagues/by-queue":                   {{30, 10 * time.Second}, {500, 10 * time.Minute}},
+	"/lol/league-exp/v4/entries":                              {{50, 10 * time.Second}},
+	"/lol/match/v5/matches":                                   {{2000, 10 * time.Second}},
+	"/lol/match/v5/matches/by-puuid":                          {{2000, 10 * time.Second}},
+	"/lol/platform/v3/champion-rotations":                     {{30, 10 * time.Second}, {500, 10 * time.Minute}},
+	"/lol/spectator/v5/active-games/by-summoner":              {{20000, 10 * time.Second}, {1200000, 10 * time.Minute}},
+	"/lol/spectator/v5/featured-games":                        {{20000, 10 * time.Second}, {1200000, 10 * time.Minute}},
+	"/lol/status/v4/platform-data":                            {{20000, 10 * time.Second}, {1200000, 10 * time.Minute}},
+	"/lol/summoner/v4/summoners":                              {{1600, time.Minute}},
+	"/lol/summoner/v4/summoners/by-account":                   {{1600, time.Minute}},
+	"/lol/summoner/v4/summoners/by-puuid":                     {{1600, time.Minute}},
+	"/riot/account/v1/accounts/by-puuid":                      {{1000, time.Minute}},
+	"/riot/account/v1/accounts/by-riot-id":                    {{1000, time.Minute}},
+	"/riot/account/v1/active-shards/by-game":                  {{20000, 10 * time.Second}, {1200000, 10 * time.Minute}},
+	"/tft/match/v1/matches":                                   {{200, 10 * time.Second}},
+	"/tft/match/v1/matches/by-puuid":                          {{600, 10 * time.Second}},
+	"/tft/summoner/v1/summoners":                              {{1600, time.Minute}},
+	"/tft/summoner/v1/summoners/by-account":                   {{1600, time.Minute}},
+	"/tft/summoner/v1/summoners/by-puuid":                     {{1600, time.Minute}},
+}
+
+var (
+	// DevelopmentKey is the preset of a development API key.
+	DevelopmentKey = Preset{
+		App:     []Limit{{20, time.Second}, {100, 2 * time.Minute}},
+		Methods: copyMethodLimits(MethodLimits),
+	}
+
+	// PersonalKey is the preset of a personal API key, which has the same
+	// limits as a development key.
+	PersonalKey = Preset{
+		App:     []Limit{{20, time.Second}, {100, 2 * time.Minute}},
+		Methods: copyMethodLimits(MethodLimits),
+	}
+
+	// ProductionKey is the preset of a production API key with the default
+	// limits. Production keys that were granted higher limits should use a
+	// custom Preset.
+	ProductionKey = Preset{
+		App:     []Limit{{500, 10 * time.Second}, {30000, 10 * time.Minute}},
+		Methods: copyMethodLimits(MethodLimits),
+	}
+)
+
+// copyMethodLimits returns a deep copy of the given method limits, so that
+// changing the limits of one preset does not change those of another.
+func copyMethodLimits(methods map[string][]Limit) map[string][]Limit {
+	c := make(map[string][]Limit, len(methods))
+	for method, limits := range methods {
+		c[method] = append([]Limit(nil), limits...)
+	}
+	return c
+}
+
+// WithPreset makes the limiter assume the limits of the preset for any quota
+// bucket whose limits have not been learned yet.
+func WithPreset(p Preset) Option {
+	return func(l *limiter) {
+		l.preset = p
+	}
+}
+
+// WithConservativeStart makes the limiter allow only one call in flight per
+// quota bucket, until the limits of the bucket are learned from a response or
+// known from the preset. This avoids a burst of calls earning HTTP 429s when a
+// process starts, at the cost of serializing its first calls. Until then,
+// Estimate reports a single limit with a zero window and a capacity of one.
+func WithConservativeStart() Option {
+	return func(l *limiter) {
+		l.conservative = true
+	}
+}
+
+// seedLocked creates the limits of the quota bucket from the preset, or the
+// limit of one call in flight in conservative mode, if the bucket does not have
+// any limits yet. The lock must be held.
+func (l *limiter) seedLocked(key Invocation) {
+	if _, ok := l.limits[key]; ok {
+		return
+	}
+	limits := l.preset.App
+	if key.Method != "" {
+		limits = l.preset.Methods[key.Method]
+	}
+	if len(limits) == 0 {
+		if !l.conservative {
+			return
+		}
+		// A zero interval counts units only while they are in flight.
+		limits = []Limit{{Requests: 1}}
+	}
+	il := l.getOrCreateInvocationLimit(key)
+	for _, lim := range limits {
+		il.SetLimitCapacity(int64(lim.Window/time.Second), lim.Requests)
+	}
+}
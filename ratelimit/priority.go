@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Priority is the class of an Invocation. When invocations wait for the same
+// quota, higher priorities are served first, and invocations of the same
+// priority are served in the order in which they called Acquire.
+type Priority int
+
+const (
+	// PriorityBackground is for bulk work, such as crawling, that can wait for
+	// all other calls.
+	PriorityBackground Priority = iota - 1
+
+	// PriorityNormal is the default priority.
+	PriorityNormal
+
+	// PriorityInteractive is for calls that a user is waiting on.
+	PriorityInteractive
+)
+
+// priorityNames maps each priority to its name.
+var priorityNames = map[Priority]string{
+	PriorityBackground:  "background",
+	PriorityNormal:      "normal",
+	PriorityInteractive: "interactive",
+}
+
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("Priority(%d)", int(p))
+}
+
+// ParsePriority returns the priority with the given name, which is one of
+// "background", "normal" and "interactive". The empty string is
+// PriorityNormal.
+func ParsePriority(name string) (Priority, error) {
+	if name == "" {
+		return PriorityNormal, nil
+	}
+	for p, n := range priorityNames {
+		if strings.EqualFold(name, n) {
+			return p, nil
+		}
+	}
+	return PriorityNormal, fmt.Errorf("unknown priority %q", name)
+}
+
+// priorityKey is the context key of the priority.
+type priorityKey struct{}
+
+// WithPriority returns a context that carries the given priority. Clients that
+// build invocations on behalf of their callers, such as apiclient, use it as
+// the priority of the invocations made with the context.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFromContext returns the priority carried by the context, or
+// PriorityNormal if there is none.
+func PriorityFromContext(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKey{}).(Priority)
+	return p
+}
+
+// WithReservedShare reserves the given share, between 0 and 1, of every limit
+// for invocations of priority p or higher. Invocations of lower priority can
+// only use the rest of each limit. For example,
+//
+//	NewLimiter(WithReservedShare(PriorityInteractive, 0.2))
+//
+// keeps a fifth of all quota free for interactive calls.
+//
+// Reservations of different priorities are nested rather than added, so
+// reserving 0.1 for PriorityInteractive and 0.3 for PriorityNormal leaves
+// background calls 70% of each limit, and normal calls 90%.
+func WithReservedShare(p Priority, share float64) Option {
+	return func(l *limiter) {
+		l.reserved[p] = share
+	}
+}
+
+// reservedShare returns the share of every limit that invocations of the given
+// priority must leave free.
+func (l *limiter) reservedShare(p Priority) float64 {
+	var share float64
+	for q, s := range l.reserved {
+		if q > p && s > share {
+			share = s
+		}
+	}
+	return share
+}
@@ -8,14 +8,13 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 )
 
-const sleepBeforeRetryAcquire = 25 * time.Millisecond
-
 // Invocation represents a specific application's invocation of the Riot API.
 type Invocation struct {
 	// ApplicationKey is any unique application identifier, typically the Riot
@@ -23,7 +22,9 @@ type Invocation struct {
 	ApplicationKey string
 
 	// Region is the region for which the method is called. Limits are enforced
-	// on a by-region basis.
+	// on a by-region basis. For methods served by regional clusters, such as
+	// match-v5, this is the routing value (e.g. "AMERICAS") instead of the
+	// platform, since each cluster has its own quota.
 	Region string
 
 	// Method is the relative method path with all options stripped. For example,
@@ -42,6 +43,11 @@ type Invocation struct {
 	// default false value is typical for most invocations, which do in fact use
 	// app quota.
 	NoAppQuota bool
+
+	// Priority is the class of the invocation. It decides the order in which
+	// waiting invocations acquire quota, but does not affect which quota they
+	// use. The default zero value is PriorityNormal.
+	Priority Priority
 }
 
 // App returns an invocation that is application-level as opposed to
@@ -53,6 +59,13 @@ func (i Invocation) App() Invocation {
 	}
 }
 
+// bucket returns the invocation that keys the method-level quota of i, which
+// is shared by invocations of all priorities.
+func (i Invocation) bucket() Invocation {
+	i.Priority = PriorityNormal
+	return i
+}
+
 // Done is a callback returned by Acquire() that signals the end of an API
 // method call. Calling Done will schedule the rate to be added back to the
 // pool at the appropriate time.
@@ -74,106 +87,291 @@ type Limiter interface {
 	// satisfied, or until the context is cancelled. Once acquired, the rate
 	// resource is reserved until Done() or Cancel() are called and return nil.
 	Acquire(ctx context.Context, inv Invocation) (Done, Cancel, error)
+
+	// TryAcquire acquires quota for the invocation if it is available now,
+	// without blocking. On success, ok is true and the rate resource is
+	// reserved as by Acquire. Otherwise, ok is false and wake is the earliest
+	// time at which the invocation is expected to succeed, or the zero time if
+	// that depends on calls that are still in flight.
+	TryAcquire(ctx context.Context, inv Invocation) (done Done, cancel Cancel, ok bool, wake time.Time, err error)
+
+	// Estimate reports the current state of the application and method limits
+	// of the invocation.
+	Estimate(ctx context.Context, inv Invocation) (Estimate, error)
 }
 
 // invocationLimit represents a rate limit for a specific type of invocation.
 type invocationLimit struct {
 	// limits maps interval length in seconds to the *singleLimit.
-	limits sync.Map
-}
-
-// Get returns the singleLimit for the interval in seconds, or nil if no limit is
-// configured for that time interval.
-func (i *invocationLimit) Get(ts int64) *singleLimit {
-	obj, ok := i.limits.Load(ts)
-	if !ok {
-		return nil
-	}
-	return obj.(*singleLimit)
-}
-
-// ForEachLimit applies the function f to each limit associated with this
-// invocation.
-func (i *invocationLimit) ForEachLimit(f func(seconds int64, limit *singleLimit) (next bool)) {
-	i.limits.Range(func(key, value interface{}) bool {
-		return f(key.(int64), value.(*singleLimit))
-	})
+	limits map[int64]*singleLimit
 }
 
 // SetLimitCapacity either modifies the stored limit or creates one with the
 // given capacity.
 func (i *invocationLimit) SetLimitCapacity(seconds, capacity int64) {
-	o, _ := i.limits.LoadOrStore(seconds, &singleLimit{
-		capacity: capacity,
-		quantity: capacity,
-	})
-	o.(*singleLimit).SetCapacity(capacity)
+	if lim, ok := i.limits[seconds]; ok {
+		lim.SetCapacity(capacity)
+		return
+	}
+	i.limits[seconds] = newSingleLimit(time.Duration(seconds)*time.Second, capacity)
 }
 
-// NewLimiter returns an in-proecss limiter.
-func NewLimiter() Limiter {
-	return &limiter{
+// NewLimiter returns an in-proecss limiter, with the given options applied.
+func NewLimiter(opts ...Option) Limiter {
+	l := &limiter{
+		clock:      SystemClock,
+		reserved:   make(map[Priority]float64),
+		limits:     make(map[Invocation]*invocationLimit),
 		methodWake: make(map[Invocation]time.Time),
+		queued:     make(map[Invocation]int),
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 type limiter struct {
-	// limits maps from Invocation to an *invocationLimit. The Invocation with
-	// empty Method field corresponds to the application-level limits.
-	limits sync.Map
-
-	// lock protects methodWake. The empty method corresponds to application
-	// limits. Service limits are also included as application limits, since they
-	// have the same underlying effect.
-	lock       sync.RWMutex
+	clock Clock
+
+	// reserved maps a priority to the share of every limit that is reserved
+	// for invocations of that priority or higher.
+	reserved map[Priority]float64
+
+	// preset holds the limits of quota buckets that have not been learned yet.
+	// If conservative is true, then buckets that are in neither allow only one
+	// call in flight.
+	preset       Preset
+	conservative bool
+
+	// lock protects all fields, and all limits. Holding a single lock allows
+	// the application and method limits of an invocation to be acquired
+	// atomically.
+	lock sync.Mutex
+
+	// limits maps from Invocation to its limits. The Invocation with empty
+	// Method field corresponds to the application-level limits.
+	limits map[Invocation]*invocationLimit
+
+	// methodWake is the time until which Riot asked us to stop calling a
+	// method, via Retry-After. The empty method corresponds to application
+	// limits. Service limits are also included as application limits, since
+	// they have the same underlying effect.
 	methodWake map[Invocation]time.Time
+
+	// waiters are the blocked Acquire calls, in the order in which they are
+	// served: by descending priority, and then by arrival. queued counts the
+	// waiters that need each quota bucket.
+	waiters []*waiter
+	queued  map[Invocation]int
+	seq     uint64
+
+	// timer runs grantLocked at timerWake, which is the earliest time at which
+	// a waiter that is not held back by another waiter expects quota to be
+	// available. There is a single timer, however many calls are waiting.
+	timer     Timer
+	timerWake time.Time
 }
 
-// getInvocationLimit returns the limit corresponding to the given invocation.
-// If no limit is configured, then this returns nil.
-func (l *limiter) getInvocationLimit(inv Invocation) *invocationLimit {
-	got, ok := l.limits.Load(inv)
-	if !ok {
-		return nil
+// waiter is an Acquire call that is waiting for quota.
+type waiter struct {
+	inv Invocation
+	seq uint64
+
+	// granted is true once the quota has been acquired on behalf of the
+	// waiter, in which case acquired holds the acquired limits and notify is
+	// closed.
+	granted  bool
+	acquired []*singleLimit
+	notify   chan struct{}
+}
+
+// before returns true if w is served before v.
+func (w *waiter) before(v *waiter) bool {
+	if w.inv.Priority != v.inv.Priority {
+		return w.inv.Priority > v.inv.Priority
 	}
-	return got.(*invocationLimit)
+	return w.seq < v.seq
 }
 
-// getOrCreateInvocationLimit returns the limit corresponding to the given
-// invocation. If it does not yet exist, then create one and return it.
-func (l *limiter) getOrCreateInvocationLimit(inv Invocation) *invocationLimit {
-	val, _ := l.limits.LoadOrStore(inv, &invocationLimit{})
-	return val.(*invocationLimit)
+// enqueueLocked adds a waiter for the invocation to the queue. The lock must
+// be held.
+func (l *limiter) enqueueLocked(inv Invocation) *waiter {
+	l.seq++
+	w := &waiter{
+		inv:    inv,
+		seq:    l.seq,
+		notify: make(chan struct{}),
+	}
+	i := sort.Search(len(l.waiters), func(i int) bool {
+		return w.before(l.waiters[i])
+	})
+	l.waiters = append(l.waiters, nil)
+	copy(l.waiters[i+1:], l.waiters[i:])
+	l.waiters[i] = w
+	l.countLocked(inv, 1)
+	return w
 }
 
-// maybeSleep returns after either all required sleeps for the invocation are
-// complete, or the context is cancelled.
-func (l *limiter) maybeSleep(ctx context.Context, inv Invocation) error {
-	l.lock.RLock()
-	appWake := l.methodWake[inv.App()]
-	methodWake := l.methodWake[inv]
-	l.lock.RUnlock()
-
-	if !appWake.IsZero() {
-		select {
-		case <-time.NewTimer(time.Until(appWake)).C:
-		case <-ctx.Done():
-			return ctx.Err()
+// removeLocked removes a waiter that was not granted from the queue. The lock
+// must be held.
+func (l *limiter) removeLocked(w *waiter) {
+	for i, v := range l.waiters {
+		if v == w {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			l.countLocked(w.inv, -1)
+			return
 		}
 	}
-	if !methodWake.IsZero() {
-		select {
-		case <-time.NewTimer(time.Until(methodWake)).C:
-		case <-ctx.Done():
-			return ctx.Err()
+}
+
+// countLocked adds delta to the number of queued waiters that need each quota
+// bucket of the invocation. The lock must be held.
+func (l *limiter) countLocked(inv Invocation, delta int) {
+	for _, key := range l.keys(inv) {
+		if n := l.queued[key] + delta; n > 0 {
+			l.queued[key] = n
+		} else {
+			delete(l.queued, key)
 		}
 	}
-	return nil
+}
+
+// grantLocked acquires quota for as many waiters as possible, in the order in
+// which they are served, and wakes only the waiters that were granted. A
+// waiter that cannot acquire quota holds back later waiters that need the same
+// exhausted quota bucket, but not those that only share its available buckets.
+// Waiters that are held back are not even checked, and the timer is set for
+// the earliest wake time of those that are not. It returns the exhausted
+// buckets, mapped to the wake time of the first waiter that needs each. The
+// lock must be held.
+func (l *limiter) grantLocked(now time.Time) map[Invocation]time.Time {
+	var (
+		// held maps the exhausted buckets to the wake time of the first
+		// waiter that needs each, and passed counts the waiters that need
+		// each bucket and stay in the queue.
+		held    = make(map[Invocation]time.Time)
+		passed  = make(map[Invocation]int)
+		wake    time.Time
+		waiting = l.waiters[:0]
+	)
+	for i, w := range l.waiters {
+		keys := l.keys(w.inv)
+		if _, blocked := l.heldWake(held, w.inv); !blocked {
+			limits, exhausted, t, ok := l.tryAcquireLocked(w.inv, now)
+			if ok {
+				w.granted = true
+				w.acquired = limits
+				close(w.notify)
+				l.countLocked(w.inv, -1)
+				continue
+			}
+			for _, key := range exhausted {
+				held[key] = t
+			}
+			if !t.IsZero() && (wake.IsZero() || t.Before(wake)) {
+				wake = t
+			}
+		}
+		waiting = append(waiting, w)
+		for _, key := range keys {
+			passed[key]++
+		}
+
+		// Once every later waiter needs the same exhausted bucket, none of
+		// them can be granted, so there is no need to check them.
+		rest := len(l.waiters) - i - 1
+		stop := false
+		for key := range held {
+			stop = stop || l.queued[key]-passed[key] == rest
+		}
+		if stop {
+			waiting = append(waiting, l.waiters[i+1:]...)
+			break
+		}
+	}
+	for i := len(waiting); i < len(l.waiters); i++ {
+		l.waiters[i] = nil
+	}
+	l.waiters = waiting
+	l.scheduleLocked(wake, now)
+	return held
+}
+
+// heldWake returns true if the invocation needs one of the given held
+// buckets, along with the time at which it is expected to acquire quota, which
+// is no earlier than the wake time of any waiter holding it back. The time is
+// zero if one of those waiters waits for a call in flight.
+func (l *limiter) heldWake(held map[Invocation]time.Time, inv Invocation) (time.Time, bool) {
+	var (
+		wake             time.Time
+		blocked, unknown bool
+	)
+	for _, key := range l.keys(inv) {
+		t, ok := held[key]
+		if !ok {
+			continue
+		}
+		blocked = true
+		if t.IsZero() {
+			unknown = true
+		} else if t.After(wake) {
+			wake = t
+		}
+	}
+	if unknown {
+		return time.Time{}, blocked
+	}
+	return wake, blocked
+}
+
+// scheduleLocked sets the timer to run grantLocked at the given wake time, or
+// stops it if the wake time is zero. The lock must be held.
+func (l *limiter) scheduleLocked(wake, now time.Time) {
+	if wake.Equal(l.timerWake) {
+		return
+	}
+	if l.timer != nil {
+		l.timer.Stop()
+		l.timer = nil
+	}
+	l.timerWake = wake
+	if wake.IsZero() {
+		return
+	}
+	var timer Timer
+	timer = l.clock.AfterFunc(wake.Sub(now), func() {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		// The timer may have been replaced after it fired.
+		if l.timer != timer {
+			return
+		}
+		l.timer = nil
+		l.timerWake = time.Time{}
+		l.grantLocked(l.clock.Now())
+	})
+	l.timer = timer
+}
+
+// getOrCreateInvocationLimit returns the limit corresponding to the given
+// invocation. If it does not yet exist, then create one and return it. The
+// lock must be held.
+func (l *limiter) getOrCreateInvocationLimit(inv Invocation) *invocationLimit {
+	il, ok := l.limits[inv]
+	if !ok {
+		il = &invocationLimit{limits: make(map[int64]*singleLimit)}
+		l.limits[inv] = il
+	}
+	return il
 }
 
 // setCapacityForInvocation takes an HTTP header containing rate capacities and
 // stores these capacitites in the limits structure corresponding to the given
-// invocation.
+// invocation. Since Riot reports every limit of the invocation, limits that
+// are not in the header, such as those of a preset, are removed. New limits
+// count the calls that are in flight on the removed limits. The lock must be
+// held.
 func (l *limiter) setCapacityForInvocation(header string, inv Invocation) error {
 	limits, err := headerIntMap(header)
 	if err != nil {
@@ -181,16 +379,39 @@ func (l *limiter) setCapacityForInvocation(header string, inv Invocation) error
 	}
 	if len(limits) != 0 {
 		il := l.getOrCreateInvocationLimit(inv)
+		var added []*singleLimit
 		for seconds, capacity := range limits {
+			_, ok := il.limits[seconds]
 			il.SetLimitCapacity(seconds, capacity)
+			if !ok {
+				added = append(added, il.limits[seconds])
+			}
+		}
+		// Every call in flight holds the removed limit that has existed the
+		// longest, which is the one with the most calls in flight.
+		var removed *singleLimit
+		for seconds, lim := range il.limits {
+			if _, ok := limits[seconds]; !ok {
+				if removed == nil || lim.inflight > removed.inflight {
+					removed = lim
+				}
+				delete(il.limits, seconds)
+			}
+		}
+		if removed != nil && removed.inflight > 0 {
+			for _, lim := range added {
+				lim.inflight += removed.inflight
+			}
+			removed.successors = append(removed.successors, added...)
 		}
 	}
 	return nil
 }
 
 // matchRiotCounts parses the header containing counts, and reconciles them to
-// the invocationLimit corresponding to the given Invocation.
-func (l *limiter) matchRiotCounts(header string, inv Invocation) error {
+// the invocationLimit corresponding to the given Invocation. The lock must be
+// held.
+func (l *limiter) matchRiotCounts(header string, inv Invocation, now time.Time) error {
 	counts, err := headerIntMap(header)
 	if err != nil {
 		return err
@@ -198,96 +419,141 @@ func (l *limiter) matchRiotCounts(header string, inv Invocation) error {
 	if len(counts) != 0 {
 		il := l.getOrCreateInvocationLimit(inv)
 		for seconds, q := range counts {
-			got := il.Get(seconds)
-			if got != nil {
-				got.MatchRiotCounts(q, time.Duration(seconds)*time.Second)
+			if got, ok := il.limits[seconds]; ok {
+				got.MatchRiotCounts(q, now)
 			}
 		}
 	}
 	return nil
 }
 
-// cancelAllAcquired cancels all acquired limits in the given map.
-func cancelAllAcquired(acquired map[int64]*singleLimit) {
-	for _, lim := range acquired {
-		lim.Cancel()
+// keys returns the quota buckets used by the invocation.
+func (l *limiter) keys(inv Invocation) []Invocation {
+	keys := []Invocation{inv.bucket()}
+	if !inv.NoAppQuota {
+		keys = append(keys, inv.App())
 	}
+	return keys
 }
 
-// acquireAllOrCancel acquires all time interval quota for the given
-// invocation, returning the acquired limits and true on success. If any
-// interval quota cannot be acquired, then return nil and false.
-func (l *limiter) acquireAllOrCancel(inv Invocation) (map[int64]*singleLimit, bool) {
-	limits := l.getInvocationLimit(inv)
-	if limits == nil {
-		return nil, true
+// tryAcquireLocked acquires all application and method quota for the given
+// invocation, and returns the acquired limits and true on success. Otherwise,
+// nothing is acquired, and it returns the exhausted quota buckets and the time
+// at which quota is expected to be available, which is the zero time if it
+// depends on in-flight calls being released. The lock must be held.
+func (l *limiter) tryAcquireLocked(inv Invocation, now time.Time) ([]*singleLimit, []Invocation, time.Time, bool) {
+	var (
+		wake      = now
+		unknown   bool
+		limits    []*singleLimit
+		exhausted []Invocation
+		reserved  = l.reservedShare(inv.Priority)
+	)
+	for _, key := range l.keys(inv) {
+		l.seedLocked(key)
+		available := true
+		if w := l.methodWake[key]; w.After(now) {
+			available = false
+			if w.After(wake) {
+				wake = w
+			}
+		}
+		if il, ok := l.limits[key]; ok {
+			for _, lim := range il.limits {
+				limits = append(limits, lim)
+				if lim.Available(now, reserved) {
+					continue
+				}
+				available = false
+				w := lim.WakeTime(now, reserved)
+				if w.IsZero() {
+					unknown = true
+				} else if w.After(wake) {
+					wake = w
+				}
+			}
+		}
+		if !available {
+			exhausted = append(exhausted, key)
+		}
 	}
-
-	acquired := make(map[int64]*singleLimit)
-	allAcquired := true
-	limits.ForEachLimit(func(seconds int64, lim *singleLimit) bool {
-		if lim.Acquire() {
-			acquired[seconds] = lim
-			return true
+	if len(exhausted) > 0 {
+		if unknown {
+			return nil, exhausted, time.Time{}, false
 		}
-		allAcquired = false
-		return false
-	})
-
-	if allAcquired {
-		return acquired, true
+		return nil, exhausted, wake, false
 	}
-
-	cancelAllAcquired(acquired)
-
-	return nil, false
+	for _, lim := range limits {
+		lim.Acquire()
+	}
+	return limits, nil, time.Time{}, true
 }
 
 // Acquire blocks until all configured limits for the invocation are satisfied,
 // or until the context is cancelled. Once acquired, the rate resource is
 // reserved until Done() or Cancel() are called and return nil.
 func (l *limiter) Acquire(ctx context.Context, inv Invocation) (Done, Cancel, error) {
-	err := l.maybeSleep(ctx, inv)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	var (
-		appAcquired, acquired       map[int64]*singleLimit
-		appAllAcquired, allAcquired bool
-	)
-
-	for {
-		if inv.NoAppQuota {
-			appAllAcquired = true
+	l.lock.Lock()
+	w := l.enqueueLocked(inv)
+	l.grantLocked(l.clock.Now())
+	l.lock.Unlock()
+
+	// Block until quota is granted on behalf of the waiter, which happens as
+	// soon as it is freed, in the order in which waiters are served.
+	if err := l.clock.Wait(ctx, w.notify); err != nil {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		if w.granted {
+			for _, lim := range w.acquired {
+				lim.Cancel()
+			}
 		} else {
-			appAcquired, appAllAcquired = l.acquireAllOrCancel(inv.App())
+			l.removeLocked(w)
 		}
+		l.grantLocked(l.clock.Now())
+		return nil, nil, err
+	}
+	done, cancel := l.callbacks(inv, w.acquired)
+	return done, cancel, nil
+}
 
-		if appAllAcquired {
-			acquired, allAcquired = l.acquireAllOrCancel(inv)
-			if allAcquired {
-				break
-			}
-			cancelAllAcquired(appAcquired)
-		}
-		// Sleep before retrying, up until cancellation.
-		select {
-		case <-time.NewTimer(sleepBeforeRetryAcquire).C:
-		case <-ctx.Done():
-			return nil, nil, ctx.Err()
-		}
+// TryAcquire acquires quota for the invocation if it is available now, without
+// blocking. Invocations that are waiting in Acquire for the same quota are
+// served first, unless they have a lower priority.
+func (l *limiter) TryAcquire(ctx context.Context, inv Invocation) (Done, Cancel, bool, time.Time, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	now := l.clock.Now()
+	w := l.enqueueLocked(inv)
+	held := l.grantLocked(now)
+	if !w.granted {
+		// The waiter either holds its exhausted buckets, or is held back by
+		// another waiter.
+		wake, _ := l.heldWake(held, inv)
+		l.removeLocked(w)
+		l.grantLocked(now)
+		return nil, nil, false, wake, nil
 	}
+	done, cancel := l.callbacks(inv, w.acquired)
+	return done, cancel, true, time.Time{}, nil
+}
 
-	var refundOnce, cancelOnce sync.Once
+// callbacks returns the Done and Cancel callbacks of an invocation for which
+// the given limits were acquired.
+func (l *limiter) callbacks(inv Invocation, acquired []*singleLimit) (Done, Cancel) {
+	// Acquired units are released exactly once, by whichever of Done and
+	// Cancel is called first.
+	var releaseOnce sync.Once
 
 	done := func(res *http.Response) error {
-		refundOnce.Do(func() {
-			for seconds, lim := range appAcquired {
-				lim.AddQuantity(1, time.Duration(seconds)*time.Second)
-			}
-			for seconds, lim := range acquired {
-				lim.AddQuantity(1, time.Duration(seconds)*time.Second)
+		l.lock.Lock()
+		defer l.lock.Unlock()
+
+		now := l.clock.Now()
+		defer l.grantLocked(now)
+		releaseOnce.Do(func() {
+			for _, lim := range acquired {
+				lim.Release(now)
 			}
 		})
 
@@ -300,23 +566,24 @@ func (l *limiter) Acquire(ctx context.Context, inv Invocation) (Done, Cancel, er
 			retryType := strings.TrimSpace(res.Header.Get("X-Rate-Limit-Type"))
 
 			appKey := inv.App()
+			methodKey := inv.bucket()
 
 			if appLimit != "" {
-				err = l.setCapacityForInvocation(appLimit, appKey)
+				err := l.setCapacityForInvocation(appLimit, appKey)
 				if err != nil {
 					return err
 				}
-				err = l.matchRiotCounts(appCount, appKey)
+				err = l.matchRiotCounts(appCount, appKey, now)
 				if err != nil {
 					return err
 				}
 			}
 			if methodLimit != "" {
-				err = l.setCapacityForInvocation(methodLimit, inv)
+				err := l.setCapacityForInvocation(methodLimit, methodKey)
 				if err != nil {
 					return err
 				}
-				err = l.matchRiotCounts(methodCount, inv)
+				err = l.matchRiotCounts(methodCount, methodKey, now)
 				if err != nil {
 					return err
 				}
@@ -326,33 +593,35 @@ func (l *limiter) Acquire(ctx context.Context, inv Invocation) (Done, Cancel, er
 				if err != nil {
 					return err
 				}
-				until := time.Now().Add(time.Duration(retrySeconds) * time.Second)
+				until := now.Add(time.Duration(retrySeconds) * time.Second)
 				var sleepKey Invocation
 				// Method sleeps are tied to this specific invocation.
 				if retryType == "method" {
-					sleepKey = inv
+					sleepKey = methodKey
 				} else {
-					sleepKey = inv.App()
+					sleepKey = appKey
 				}
-				l.lock.Lock()
 				if until.After(l.methodWake[sleepKey]) {
 					l.methodWake[sleepKey] = until
 				}
-				l.lock.Unlock()
 			}
 		}
 		return nil
 	}
 
 	cancel := func() error {
-		cancelOnce.Do(func() {
-			cancelAllAcquired(appAcquired)
-			cancelAllAcquired(acquired)
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		releaseOnce.Do(func() {
+			for _, lim := range acquired {
+				lim.Cancel()
+			}
+			l.grantLocked(l.clock.Now())
 		})
 		return nil
 	}
 
-	return done, cancel, nil
+	return done, cancel
 }
 
 // headerIntMap takes a string representing rates for seconds intervals like
@@ -4,11 +4,13 @@ package client
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/yuhanfang/riot/external"
 	"github.com/yuhanfang/riot/ratelimit"
@@ -21,11 +23,10 @@ type client struct {
 	d    external.Doer
 }
 
-// Acquire acquires quota for the given invocation. The caller must call done()
-// or cancel() within one minute of a successful call, or the quota will be
-// assumed to have been used, and will refresh after the maximum time.
-func (c *client) Acquire(ctx context.Context, inv ratelimit.Invocation) (ratelimit.Done, ratelimit.Cancel, error) {
-	address := c.base.String() + "/acquire/" + inv.ApplicationKey + "/" + inv.Region
+// post sends the form fields describing the invocation to the method of the
+// server, and returns the response.
+func (c *client) post(ctx context.Context, method string, inv ratelimit.Invocation) (*http.Response, error) {
+	address := c.base.String() + "/" + method + "/" + inv.ApplicationKey + "/" + inv.Region
 	values := url.Values(make(map[string][]string))
 	if inv.Method != "" {
 		values.Add("method", inv.Method)
@@ -36,16 +37,73 @@ func (c *client) Acquire(ctx context.Context, inv ratelimit.Invocation) (ratelim
 	if inv.NoAppQuota {
 		values.Add("noappquota", "T")
 	}
+	if inv.Priority != ratelimit.PriorityNormal {
+		values.Add("priority", inv.Priority.String())
+	}
 	req, err := http.NewRequest("POST", address, strings.NewReader(values.Encode()))
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req = req.WithContext(ctx)
-	res, err := c.d.Do(req)
+	return c.d.Do(req)
+}
+
+// Acquire acquires quota for the given invocation. The caller must call done()
+// or cancel() within one minute of a successful call, or the quota will be
+// assumed to have been used, and will refresh after the maximum time.
+func (c *client) Acquire(ctx context.Context, inv ratelimit.Invocation) (ratelimit.Done, ratelimit.Cancel, error) {
+	res, err := c.post(ctx, "acquire", inv)
 	err = getError(res, err)
 	if err != nil {
 		return nil, nil, err
 	}
+	done, cancel, err := c.callbacks(ctx, res)
+	return done, cancel, err
+}
+
+// TryAcquire acquires quota for the given invocation if it is available now.
+// As with Acquire, the caller must call done() or cancel() within one minute of
+// a successful call.
+func (c *client) TryAcquire(ctx context.Context, inv ratelimit.Invocation) (ratelimit.Done, ratelimit.Cancel, bool, time.Time, error) {
+	res, err := c.post(ctx, "tryacquire", inv)
+	if err == nil && res.StatusCode == http.StatusTooManyRequests {
+		defer res.Body.Close()
+		b, err := ioutil.ReadAll(res.Body)
+		if err != nil || len(b) == 0 {
+			return nil, nil, false, time.Time{}, err
+		}
+		wake, err := time.Parse(time.RFC3339Nano, string(b))
+		return nil, nil, false, wake, err
+	}
+	err = getError(res, err)
+	if err != nil {
+		return nil, nil, false, time.Time{}, err
+	}
+	done, cancel, err := c.callbacks(ctx, res)
+	if err != nil {
+		return nil, nil, false, time.Time{}, err
+	}
+	return done, cancel, true, time.Time{}, nil
+}
+
+// Estimate reports the current state of the application and method limits of
+// the invocation, as tracked by the server.
+func (c *client) Estimate(ctx context.Context, inv ratelimit.Invocation) (ratelimit.Estimate, error) {
+	var est ratelimit.Estimate
+	res, err := c.post(ctx, "estimate", inv)
+	err = getError(res, err)
+	if err != nil {
+		return est, err
+	}
+	defer res.Body.Close()
+	err = json.NewDecoder(res.Body).Decode(&est)
+	return est, err
+}
+
+// callbacks reads the token from a successful acquisition, and returns the
+// callbacks that finalize it.
+func (c *client) callbacks(ctx context.Context, res *http.Response) (ratelimit.Done, ratelimit.Cancel, error) {
 	tok, err := ioutil.ReadAll(res.Body)
 	res.Body.Close()
 	if err != nil {
@@ -4,7 +4,7 @@
 // reference client implementation.
 //
 // Usage example:
-// 		ratelimit_server --port=8080
+// 		ratelimit_server --port=8080 --interactive_share=0.2 --preset=production
 package main
 
 import (
@@ -13,14 +13,41 @@ import (
 	"log"
 	"net/http"
 
+	"github.com/yuhanfang/riot/ratelimit"
 	"github.com/yuhanfang/riot/ratelimit/service/server"
 )
 
-var port = flag.Int("port", 8080, "server port")
+var (
+	port             = flag.Int("port", 8080, "server port")
+	interactiveShare = flag.Float64("interactive_share", 0, "share of every limit reserved for interactive requests")
+	normalShare      = flag.Float64("normal_share", 0, "share of every limit reserved for normal and interactive requests")
+	preset           = flag.String("preset", "", "limits assumed before they are learned: development, personal or production")
+	conservative     = flag.Bool("conservative", false, "allow one request in flight per quota bucket until its limits are known")
+)
+
+var presets = map[string]ratelimit.Preset{
+	"development": ratelimit.DevelopmentKey,
+	"personal":    ratelimit.PersonalKey,
+	"production":  ratelimit.ProductionKey,
+}
 
 func main() {
 	flag.Parse()
-	http.Handle("/", server.New())
+	opts := []ratelimit.Option{
+		ratelimit.WithReservedShare(ratelimit.PriorityInteractive, *interactiveShare),
+		ratelimit.WithReservedShare(ratelimit.PriorityNormal, *normalShare),
+	}
+	if *preset != "" {
+		p, ok := presets[*preset]
+		if !ok {
+			log.Fatalf("unknown preset %q", *preset)
+		}
+		opts = append(opts, ratelimit.WithPreset(p))
+	}
+	if *conservative {
+		opts = append(opts, ratelimit.WithConservativeStart())
+	}
+	http.Handle("/", server.New(opts...))
 	log.Println("listening on port", *port)
 	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", *port), nil))
 }
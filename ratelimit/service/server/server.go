@@ -14,6 +14,19 @@
 //         noappquota: if set to T or t, indicates that the request should count
 //           towards (possibly uniquified) method-level quota, but not application
 //           quota.
+//         priority: one of background, normal or interactive. Requests of
+//           higher priority acquire quota first. If omitted, then the request
+//           has normal priority.
+//
+//     POST /tryacquire/:API_KEY/:REGION
+//       Like /acquire, but returns immediately. If quota is not available, then
+//       it returns HTTP 429 with the earliest time at which the request is
+//       expected to succeed, in RFC 3339 format, or an empty body if the time
+//       is not known. The method supports the same form fields as /acquire.
+//
+//     POST /estimate/:API_KEY/:REGION
+//       Returns the ratelimit.Estimate of the application and method limits as
+//       JSON. The method supports the same form fields as /acquire.
 //
 //     POST /done/:TOKEN
 //       Marks the request with the given token as complete, so that all
@@ -28,6 +41,7 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
@@ -56,11 +70,11 @@ type server struct {
 	limiter ratelimit.Limiter
 }
 
-func (s *server) HandleAcquire(w http.ResponseWriter, r *http.Request) {
+// invocation parses the invocation described by the request.
+func invocation(r *http.Request) (ratelimit.Invocation, error) {
 	err := r.ParseForm()
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return ratelimit.Invocation{}, err
 	}
 
 	vars := mux.Vars(r)
@@ -69,13 +83,26 @@ func (s *server) HandleAcquire(w http.ResponseWriter, r *http.Request) {
 	method := r.Form.Get("method")
 	uniquifier := r.Form.Get("uniquifier")
 	noAppQuota := r.Form.Get("noappquota")
+	priority, err := ratelimit.ParsePriority(r.Form.Get("priority"))
+	if err != nil {
+		return ratelimit.Invocation{}, err
+	}
 
-	inv := ratelimit.Invocation{
+	return ratelimit.Invocation{
 		ApplicationKey: key,
 		Region:         strings.ToUpper(region),
 		Method:         strings.ToLower(method),
 		Uniquifier:     uniquifier,
 		NoAppQuota:     noAppQuota == "t" || noAppQuota == "T",
+		Priority:       priority,
+	}, nil
+}
+
+func (s *server) HandleAcquire(w http.ResponseWriter, r *http.Request) {
+	inv, err := invocation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
 	done, cancel, err := s.limiter.Acquire(r.Context(), inv)
@@ -83,7 +110,50 @@ func (s *server) HandleAcquire(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	s.register(w, done, cancel)
+}
+
+func (s *server) HandleTryAcquire(w http.ResponseWriter, r *http.Request) {
+	inv, err := invocation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	done, cancel, ok, wake, err := s.limiter.TryAcquire(r.Context(), inv)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		w.WriteHeader(http.StatusTooManyRequests)
+		if !wake.IsZero() {
+			fmt.Fprintf(w, "%s", wake.Format(time.RFC3339Nano))
+		}
+		return
+	}
+	s.register(w, done, cancel)
+}
+
+func (s *server) HandleEstimate(w http.ResponseWriter, r *http.Request) {
+	inv, err := invocation(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	est, err := s.limiter.Estimate(r.Context(), inv)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(est)
+}
 
+// register writes a new token for the acquired quota, and closes out the
+// quota automatically if the token is not marked done or cancelled in time.
+func (s *server) register(w http.ResponseWriter, done ratelimit.Done, cancel ratelimit.Cancel) {
 	// Defined later in the same thread.
 	var timer *time.Timer
 
@@ -103,6 +173,7 @@ func (s *server) HandleAcquire(w http.ResponseWriter, r *http.Request) {
 	for {
 		u, err := uuid.NewV4()
 		if err != nil {
+			cancel()
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -162,16 +233,19 @@ func (s *server) HandleCancel(w http.ResponseWriter, r *http.Request) {
 }
 
 // New returns an HTTP handler that implements the rate limit service. The
-// return value can be used via code like:
+// given options configure the underlying limiter. The return value can be
+// used via code like:
 // 		r := New()
 //    http.Handle("/", r)
-func New() http.Handler {
+func New(opts ...ratelimit.Option) http.Handler {
 	s := server{
 		tokens:  make(map[string]*callbacksForToken),
-		limiter: ratelimit.NewLimiter(),
+		limiter: ratelimit.NewLimiter(opts...),
 	}
 	r := mux.NewRouter()
 	r.HandleFunc("/acquire/{key}/{region}", s.HandleAcquire).Methods("POST")
+	r.HandleFunc("/tryacquire/{key}/{region}", s.HandleTryAcquire).Methods("POST")
+	r.HandleFunc("/estimate/{key}/{region}", s.HandleEstimate).Methods("POST")
 	r.HandleFunc("/done/{token}", s.HandleDone).Methods("POST")
 	r.HandleFunc("/cancel/{token}", s.HandleCancel).Methods("POST")
 	return r
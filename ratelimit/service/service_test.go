@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/yuhanfang/riot/ratelimit"
 	"github.com/yuhanfang/riot/ratelimit/service/client"
@@ -62,3 +63,93 @@ func TestEndToEnd(t *testing.T) {
 		t.Fatal("done should fail after cancel")
 	}
 }
+
+func TestPriority(t *testing.T) {
+	s := server.New(ratelimit.WithReservedShare(ratelimit.PriorityInteractive, 0.5))
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := client.New(http.DefaultClient, u)
+
+	ctx := context.Background()
+	inv := ratelimit.Invocation{
+		ApplicationKey: "key",
+		Region:         "NA1",
+		Method:         "/foo/bar",
+	}
+	done, _, err := c.Acquire(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := make(http.Header)
+	header.Set("X-Method-Rate-Limit", "2:3600")
+	header.Set("X-Method-Rate-Limit-Count", "1:3600")
+	if err := done(&http.Response{Header: header}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The remaining unit is reserved for interactive requests.
+	short, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancel()
+	if _, _, err := c.Acquire(short, inv); err == nil {
+		t.Error("normal request acquired reserved quota")
+	}
+	inv.Priority = ratelimit.PriorityInteractive
+	if _, _, err := c.Acquire(ctx, inv); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTryAcquireAndEstimate(t *testing.T) {
+	ts := httptest.NewServer(server.New())
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := client.New(http.DefaultClient, u)
+
+	ctx := context.Background()
+	inv := ratelimit.Invocation{
+		ApplicationKey: "key",
+		Region:         "NA1",
+		Method:         "/foo/bar",
+	}
+	done, _, ok, _, err := c.TryAcquire(ctx, inv)
+	if err != nil || !ok {
+		t.Fatalf("got ok %v and error %v, want success", ok, err)
+	}
+	header := make(http.Header)
+	header.Set("X-Method-Rate-Limit", "1:3600")
+	header.Set("X-Method-Rate-Limit-Count", "1:3600")
+	if err := done(&http.Response{Header: header}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, ok, wake, err := c.TryAcquire(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("acquired exhausted quota")
+	}
+	if d := time.Until(wake); d < 3590*time.Second || d > 3600*time.Second {
+		t.Errorf("got wake in %v, want in about an hour", d)
+	}
+
+	est, err := c.Estimate(ctx, inv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(est.Method) != 1 || est.Method[0].Window != time.Hour || est.Method[0].Capacity != 1 || est.Method[0].Remaining != 0 {
+		t.Errorf("got method estimates %+v, want none remaining of 1 per hour", est.Method)
+	}
+	if !est.Wake.Equal(wake) {
+		t.Errorf("got estimated wake %v, want %v", est.Wake, wake)
+	}
+}
@@ -0,0 +1,306 @@
+package simulation
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yuhanfang/riot/ratelimit"
+)
+
+// Clock is a virtual ratelimit.Clock. Time only moves when Advance or
+// AdvanceToNext is called, so a simulation can cover hours of traffic in
+// milliseconds of real time. It is illegal to construct an instance directly.
+// Use NewClock to return a valid instance. The Clock is threadsafe.
+//
+// Goroutines started with Go are tracked by the clock, which advances only
+// once all of them have returned or are blocked in Wait, so that no goroutine
+// observes a time at which it would not yet have run. Tracked goroutines must
+// not block for long in any other way, such as on a channel of the caller.
+// Functions scheduled with AfterFunc are called by the goroutine that
+// advances the clock.
+type Clock struct {
+	lock   sync.Mutex
+	now    time.Time
+	timers timerHeap
+	seq    int64
+
+	// running counts the tracked goroutines that are not blocked in Wait, and
+	// live counts those that have not returned. waits are the pending calls
+	// to Wait of tracked goroutines. settled is signaled whenever running
+	// drops.
+	running int
+	live    int
+	waits   map[*wait]bool
+	settled *sync.Cond
+}
+
+var _ ratelimit.Clock = (*Clock)(nil)
+
+// NewClock returns a virtual clock that starts at the given time.
+func NewClock(start time.Time) *Clock {
+	c := &Clock{
+		now:   start,
+		waits: make(map[*wait]bool),
+	}
+	c.settled = sync.NewCond(&c.lock)
+	return c
+}
+
+func (c *Clock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+func (c *Clock) AfterFunc(d time.Duration, f func()) ratelimit.Timer {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.seq++
+	t := &timer{
+		clock: c,
+		when:  c.now.Add(d),
+		seq:   c.seq,
+		f:     f,
+	}
+	heap.Push(&c.timers, t)
+	return t
+}
+
+// goroutine identifies a goroutine started with Go, through the context that
+// is passed to it.
+type goroutine struct {
+	clock   *Clock
+	waiting bool
+}
+
+// goroutineKey is the context key of the goroutine started with Go.
+type goroutineKey struct{}
+
+// wait is a pending call to Wait.
+type wait struct {
+	wake <-chan struct{}
+	done <-chan struct{}
+}
+
+// ready returns true if the wait can return without the clock advancing.
+func (w *wait) ready() bool {
+	return closed(w.wake) || closed(w.done)
+}
+
+// closed returns true if the channel is closed. The channel must never be
+// sent on.
+func closed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+// Wait implements ratelimit.Clock. While a goroutine started with Go is
+// blocked in Wait, it does not hold back Settle. The goroutine is recognized
+// by the context passed to it, or a context derived from it. Calls from other
+// goroutines are not tracked, and never hold back Settle.
+func (c *Clock) Wait(ctx context.Context, wake <-chan struct{}) error {
+	g, _ := ctx.Value(goroutineKey{}).(*goroutine)
+	if g != nil && g.clock == c {
+		w := &wait{wake: wake, done: ctx.Done()}
+		c.lock.Lock()
+		if g.waiting {
+			c.lock.Unlock()
+			panic("simulation: context of a tracked goroutine is used by another goroutine")
+		}
+		g.waiting = true
+		c.waits[w] = true
+		c.running--
+		c.settled.Broadcast()
+		c.lock.Unlock()
+		defer func() {
+			c.lock.Lock()
+			g.waiting = false
+			delete(c.waits, w)
+			c.running++
+			c.lock.Unlock()
+		}()
+	}
+
+	select {
+	case <-wake:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Go calls f in a new goroutine that is tracked by the clock until f returns.
+// The context passed to f identifies the goroutine in calls to Wait, and must
+// not be used by other goroutines.
+func (c *Clock) Go(ctx context.Context, f func(ctx context.Context)) {
+	ctx = context.WithValue(ctx, goroutineKey{}, &goroutine{clock: c})
+	c.lock.Lock()
+	c.running++
+	c.live++
+	c.lock.Unlock()
+	go func() {
+		defer func() {
+			c.lock.Lock()
+			c.running--
+			c.live--
+			c.settled.Broadcast()
+			c.lock.Unlock()
+		}()
+		f(ctx)
+	}()
+}
+
+// Goroutines returns the number of goroutines started with Go that have not
+// returned.
+func (c *Clock) Goroutines() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.live
+}
+
+// Pending returns the number of timers that have not fired or been stopped.
+func (c *Clock) Pending() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return len(c.timers)
+}
+
+// Advance settles the clock, and then moves it forward by the given duration,
+// firing timers in order of their deadlines. The clock settles after each
+// firing, so that woken goroutines observe the time at which their timer
+// fired.
+func (c *Clock) Advance(d time.Duration) {
+	c.Settle()
+	c.lock.Lock()
+	end := c.now.Add(d)
+	c.lock.Unlock()
+	for c.fireNext(end) {
+		c.Settle()
+	}
+	c.lock.Lock()
+	if end.After(c.now) {
+		c.now = end
+	}
+	c.lock.Unlock()
+}
+
+// AdvanceToNext settles the clock, and then moves it to the deadline of the
+// earliest pending timer, fires it and settles again. It returns false if
+// there are no pending timers.
+func (c *Clock) AdvanceToNext() bool {
+	c.Settle()
+	c.lock.Lock()
+	if len(c.timers) == 0 {
+		c.lock.Unlock()
+		return false
+	}
+	next := c.timers[0].when
+	c.lock.Unlock()
+	fired := c.fireNext(next)
+	c.Settle()
+	return fired
+}
+
+// fireNext fires the earliest pending timer if its deadline is not after the
+// given time, and returns false otherwise. Timers with the same deadline fire
+// one at a time, in the order in which they were created.
+func (c *Clock) fireNext(limit time.Time) bool {
+	c.lock.Lock()
+	if len(c.timers) == 0 || c.timers[0].when.After(limit) {
+		c.lock.Unlock()
+		return false
+	}
+	t := heap.Pop(&c.timers).(*timer)
+	if t.when.After(c.now) {
+		c.now = t.when
+	}
+	t.fired = true
+	c.lock.Unlock()
+	t.f()
+	return true
+}
+
+// Settle blocks until every goroutine started with Go has returned, or is
+// blocked in Wait until the clock advances.
+func (c *Clock) Settle() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for !c.settledLocked() {
+		c.settled.Wait()
+	}
+}
+
+// settledLocked returns true if no tracked goroutine can make progress without
+// the clock advancing. The lock must be held.
+func (c *Clock) settledLocked() bool {
+	if c.running > 0 {
+		return false
+	}
+	for w := range c.waits {
+		if w.ready() {
+			return false
+		}
+	}
+	return true
+}
+
+// timer is a virtual ratelimit.Timer.
+type timer struct {
+	clock *Clock
+	when  time.Time
+	seq   int64 // Breaks ties between timers with the same deadline.
+	index int   // Index in the heap, maintained by timerHeap.
+	fired bool
+	f     func()
+}
+
+func (t *timer) Stop() bool {
+	t.clock.lock.Lock()
+	defer t.clock.lock.Unlock()
+	if t.fired || t.index < 0 {
+		return false
+	}
+	heap.Remove(&t.clock.timers, t.index)
+	return true
+}
+
+// timerHeap orders timers by deadline, and then by creation.
+type timerHeap []*timer
+
+func (h timerHeap) Len() int {
+	return len(h)
+}
+
+func (h timerHeap) Less(i, j int) bool {
+	if !h[i].when.Equal(h[j].when) {
+		return h[i].when.Before(h[j].when)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h timerHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
+}
+
+func (h *timerHeap) Push(x interface{}) {
+	t := x.(*timer)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *timerHeap) Pop() interface{} {
+	old := *h
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	t.index = -1
+	return t
+}
@@ -0,0 +1,173 @@
+package simulation
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yuhanfang/riot/ratelimit"
+)
+
+// Limit is a rate limit of a number of requests per time window.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// Outage is a period, relative to the start of the simulation, during which
+// every call fails with a service rate limit, as Riot does when an
+// underlying service is overloaded.
+type Outage struct {
+	Start, End time.Duration
+
+	// RetryAfter is the Retry-After returned during the outage. Zero means
+	// that the header is omitted, as Riot does for most service limits.
+	RetryAfter time.Duration
+}
+
+// Endpoint is a scripted fake Riot API. It enforces application and method
+// limits with fixed windows that start at the first call after the previous
+// window expires, like Riot does, and reports limits and counts in the
+// X-App-Rate-Limit(-Count) and X-Method-Rate-Limit(-Count) headers. Calls
+// that exceed a limit fail with HTTP 429, Retry-After and X-Rate-Limit-Type,
+// and are not counted.
+type Endpoint struct {
+	// AppLimits are shared by all methods of an application key and region.
+	AppLimits []Limit
+
+	// MethodLimits maps Invocation.Method to the limits of the method.
+	// Methods that are not in the map use DefaultMethodLimits.
+	MethodLimits        map[string][]Limit
+	DefaultMethodLimits []Limit
+
+	// Outages fail every call with a service rate limit.
+	Outages []Outage
+}
+
+// window counts calls in a fixed time window.
+type window struct {
+	start time.Time
+	count int
+}
+
+// endpointState is the state of an Endpoint during a simulation.
+type endpointState struct {
+	e     *Endpoint
+	start time.Time
+
+	// windows maps a quota bucket to a window per limit.
+	windows map[ratelimit.Invocation][]window
+}
+
+func newEndpointState(e *Endpoint, start time.Time) *endpointState {
+	return &endpointState{
+		e:       e,
+		start:   start,
+		windows: make(map[ratelimit.Invocation][]window),
+	}
+}
+
+// check expires the windows of the bucket, and returns the time until the
+// bucket has quota, or zero if it has quota now.
+func (s *endpointState) check(bucket ratelimit.Invocation, limits []Limit, now time.Time) time.Duration {
+	windows := s.windows[bucket]
+	if len(windows) != len(limits) {
+		windows = make([]window, len(limits))
+		s.windows[bucket] = windows
+	}
+	var wait time.Duration
+	for i, l := range limits {
+		if windows[i].count == 0 || now.Sub(windows[i].start) >= l.Window {
+			windows[i] = window{start: now}
+		}
+		if windows[i].count >= l.Requests {
+			if w := windows[i].start.Add(l.Window).Sub(now); w > wait {
+				wait = w
+			}
+		}
+	}
+	return wait
+}
+
+// count counts a call against the bucket, and returns the header values of
+// its limits and counts.
+func (s *endpointState) count(bucket ratelimit.Invocation, limits []Limit) (string, string) {
+	windows := s.windows[bucket]
+	limitPieces := make([]string, len(limits))
+	countPieces := make([]string, len(limits))
+	for i, l := range limits {
+		windows[i].count++
+		seconds := int(l.Window / time.Second)
+		limitPieces[i] = fmt.Sprintf("%d:%d", l.Requests, seconds)
+		countPieces[i] = fmt.Sprintf("%d:%d", windows[i].count, seconds)
+	}
+	return strings.Join(limitPieces, ","), strings.Join(countPieces, ",")
+}
+
+// serve returns the response to the invocation at the given time.
+func (s *endpointState) serve(inv ratelimit.Invocation, now time.Time) *http.Response {
+	res := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader("{}")),
+	}
+	for _, o := range s.e.Outages {
+		if elapsed := now.Sub(s.start); elapsed >= o.Start && elapsed < o.End {
+			res.StatusCode = http.StatusTooManyRequests
+			res.Header.Set("X-Rate-Limit-Type", "service")
+			if o.RetryAfter > 0 {
+				res.Header.Set("Retry-After", retryAfter(o.RetryAfter))
+			}
+			return res
+		}
+	}
+
+	appBucket := inv.App()
+	methodBucket := ratelimit.Invocation{
+		ApplicationKey: inv.ApplicationKey,
+		Region:         inv.Region,
+		Method:         inv.Method,
+		Uniquifier:     inv.Uniquifier,
+	}
+	methodLimits, ok := s.e.MethodLimits[inv.Method]
+	if !ok {
+		methodLimits = s.e.DefaultMethodLimits
+	}
+
+	var appWait time.Duration
+	if !inv.NoAppQuota {
+		appWait = s.check(appBucket, s.e.AppLimits, now)
+	}
+	methodWait := s.check(methodBucket, methodLimits, now)
+	if appWait > 0 || methodWait > 0 {
+		res.StatusCode = http.StatusTooManyRequests
+		if appWait >= methodWait {
+			res.Header.Set("X-Rate-Limit-Type", "application")
+			res.Header.Set("Retry-After", retryAfter(appWait))
+		} else {
+			res.Header.Set("X-Rate-Limit-Type", "method")
+			res.Header.Set("Retry-After", retryAfter(methodWait))
+		}
+		return res
+	}
+
+	if !inv.NoAppQuota && len(s.e.AppLimits) > 0 {
+		limit, count := s.count(appBucket, s.e.AppLimits)
+		res.Header.Set("X-App-Rate-Limit", limit)
+		res.Header.Set("X-App-Rate-Limit-Count", count)
+	}
+	if len(methodLimits) > 0 {
+		limit, count := s.count(methodBucket, methodLimits)
+		res.Header.Set("X-Method-Rate-Limit", limit)
+		res.Header.Set("X-Method-Rate-Limit-Count", count)
+	}
+	return res
+}
+
+// retryAfter formats the duration in whole seconds, rounded up.
+func retryAfter(d time.Duration) string {
+	return strconv.Itoa(int((d + time.Second - 1) / time.Second))
+}
@@ -0,0 +1,290 @@
+// Package simulation runs a ratelimit.Limiter against a scripted fake Riot
+// API on a virtual clock.
+//
+// A simulation reports the throughput, the number of HTTP 429 responses and
+// the distribution of time spent waiting for quota, for a given limiter,
+// endpoint and workload. Because time is virtual, an hour of traffic runs in
+// well under a second of real time, so a crawler configuration can be checked
+// for 429s before it is deployed:
+//
+//	report, err := simulation.Run(simulation.Config{
+//		Endpoint: &simulation.Endpoint{
+//			AppLimits:           []simulation.Limit{{20, time.Second}, {100, 2 * time.Minute}},
+//			DefaultMethodLimits: []simulation.Limit{{2000, 10 * time.Second}},
+//		},
+//		Workers:  8,
+//		Duration: time.Hour,
+//	})
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yuhanfang/riot/ratelimit"
+)
+
+// Config configures a simulation.
+type Config struct {
+	// NewLimiter returns the limiter under test, which must use the given
+	// clock. Defaults to ratelimit.NewLimiter with ratelimit.WithClock.
+	NewLimiter func(clock ratelimit.Clock) ratelimit.Limiter
+
+	// Endpoint is the fake Riot API that serves all calls.
+	Endpoint *Endpoint
+
+	// Workers is the number of concurrent callers. Each worker makes calls
+	// back to back for the whole simulation.
+	Workers int
+
+	// Duration is the length of the simulation in virtual time.
+	Duration time.Duration
+
+	// Latency is the virtual time between acquiring quota and receiving the
+	// response of each call. Latencies below one millisecond are rounded up,
+	// so that workers retrying a failing endpoint do not spin without time
+	// passing.
+	Latency time.Duration
+
+	// Invocation returns the n-th call of the given worker. Defaults to a
+	// single method called by every worker.
+	Invocation func(worker, n int) ratelimit.Invocation
+}
+
+// minLatency is the least virtual time taken by a call.
+const minLatency = time.Millisecond
+
+// DefaultInvocation is the call made by workers when Config.Invocation is not
+// set.
+var DefaultInvocation = ratelimit.Invocation{
+	ApplicationKey: "key",
+	Region:         "NA1",
+	Method:         "/lol/summoner/v4/summoners/by-puuid",
+}
+
+// Report summarizes a simulation.
+type Report struct {
+	Duration time.Duration // Virtual time simulated.
+	Calls    int           // Calls that received a response, including 429s.
+
+	// RateLimited counts HTTP 429 responses by X-Rate-Limit-Type.
+	RateLimited map[string]int
+
+	// Wait is the distribution of time spent in Acquire, per call.
+	Wait *Histogram
+}
+
+// Throughput returns the number of successful calls per second.
+func (r *Report) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Calls-r.TotalRateLimited()) / r.Duration.Seconds()
+}
+
+// TotalRateLimited returns the number of HTTP 429 responses of every type.
+func (r *Report) TotalRateLimited() int {
+	var n int
+	for _, c := range r.RateLimited {
+		n += c
+	}
+	return n
+}
+
+func (r *Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "simulated %v: %d calls, %.3f calls/s, %d rate limited", r.Duration, r.Calls, r.Throughput(), r.TotalRateLimited())
+	var types []string
+	for t := range r.RateLimited {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	for _, t := range types {
+		fmt.Fprintf(&b, ", %d %s", r.RateLimited[t], t)
+	}
+	fmt.Fprintf(&b, "\nwait time:\n%v", r.Wait)
+	return b.String()
+}
+
+// Run simulates the configured workload, and returns a report once the
+// virtual duration has elapsed.
+func Run(config Config) (*Report, error) {
+	if config.Endpoint == nil {
+		return nil, errors.New("simulation: no endpoint configured")
+	}
+	if config.Workers < 1 {
+		return nil, errors.New("simulation: at least one worker is required")
+	}
+	newLimiter := config.NewLimiter
+	if newLimiter == nil {
+		newLimiter = func(clock ratelimit.Clock) ratelimit.Limiter {
+			return ratelimit.NewLimiter(ratelimit.WithClock(clock))
+		}
+	}
+	invocation := config.Invocation
+	if invocation == nil {
+		invocation = func(worker, n int) ratelimit.Invocation {
+			return DefaultInvocation
+		}
+	}
+
+	// Start on a whole second, so that reports are easy to read.
+	start := time.Unix(1500000000, 0)
+	clock := NewClock(start)
+	end := start.Add(config.Duration)
+	limiter := newLimiter(clock)
+	endpoint := newEndpointState(config.Endpoint, start)
+	report := &Report{
+		Duration:    config.Duration,
+		RateLimited: make(map[string]int),
+		Wait:        NewHistogram(),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	latency := config.Latency
+	if latency < minLatency {
+		latency = minLatency
+	}
+
+	var lock sync.Mutex // Protects endpoint and report.
+	for w := 0; w < config.Workers; w++ {
+		worker := w
+		clock.Go(ctx, func(ctx context.Context) {
+			for n := 0; ; n++ {
+				inv := invocation(worker, n)
+				begin := clock.Now()
+				if !begin.Before(end) || ctx.Err() != nil {
+					return
+				}
+				done, _, err := limiter.Acquire(ctx, inv)
+				if err != nil {
+					return
+				}
+				acquired := clock.Now()
+				if !acquired.Before(end) {
+					// The call would be made after the simulation ends.
+					done(nil)
+					return
+				}
+
+				// The call is in flight even if the simulation is stopped
+				// first, and is then served right away, so that the limiter
+				// sees its response.
+				responded := make(chan struct{})
+				clock.AfterFunc(latency, func() {
+					close(responded)
+				})
+				clock.Wait(ctx, responded)
+
+				lock.Lock()
+				res := endpoint.serve(inv, acquired)
+				report.Calls++
+				report.Wait.Add(acquired.Sub(begin))
+				if res.StatusCode == 429 {
+					report.RateLimited[res.Header.Get("X-Rate-Limit-Type")]++
+				}
+				lock.Unlock()
+
+				done(res)
+			}
+		})
+	}
+
+	// Advance time whenever all workers are blocked, until they all finish.
+	for {
+		clock.Settle()
+		if clock.Goroutines() == 0 {
+			return report, nil
+		}
+		if clock.Now().Before(end) && clock.AdvanceToNext() {
+			continue
+		}
+		// Either the simulation is over, or the workers are deadlocked
+		// waiting for each other. Stop the workers that are blocked.
+		cancel()
+		clock.Settle()
+		clock.AdvanceToNext()
+	}
+}
+
+// Histogram is a distribution of durations, with exponentially sized buckets.
+type Histogram struct {
+	// Bounds are the exclusive upper bounds of the buckets, except for the
+	// last bucket, which is unbounded.
+	Bounds []time.Duration
+	Counts []int
+
+	Total time.Duration
+	Max   time.Duration
+}
+
+// NewHistogram returns a histogram with buckets for zero, and for each power
+// of ten from one millisecond to one minute.
+func NewHistogram() *Histogram {
+	bounds := []time.Duration{1}
+	for d := time.Millisecond; d <= time.Minute; d *= 10 {
+		bounds = append(bounds, d)
+	}
+	return &Histogram{
+		Bounds: bounds,
+		Counts: make([]int, len(bounds)+1),
+	}
+}
+
+// Add records a duration.
+func (h *Histogram) Add(d time.Duration) {
+	i := sort.Search(len(h.Bounds), func(i int) bool {
+		return d < h.Bounds[i]
+	})
+	h.Counts[i]++
+	h.Total += d
+	if d > h.Max {
+		h.Max = d
+	}
+}
+
+// Count returns the number of recorded durations.
+func (h *Histogram) Count() int {
+	var n int
+	for _, c := range h.Counts {
+		n += c
+	}
+	return n
+}
+
+// Mean returns the mean of the recorded durations.
+func (h *Histogram) Mean() time.Duration {
+	n := h.Count()
+	if n == 0 {
+		return 0
+	}
+	return h.Total / time.Duration(n)
+}
+
+func (h *Histogram) String() string {
+	var b strings.Builder
+	var lower time.Duration
+	for i, c := range h.Counts {
+		if c > 0 {
+			if i == len(h.Bounds) {
+				fmt.Fprintf(&b, "  >= %v: %d\n", lower, c)
+			} else if i == 0 {
+				fmt.Fprintf(&b, "  0: %d\n", c)
+			} else {
+				fmt.Fprintf(&b, "  [%v, %v): %d\n", lower, h.Bounds[i], c)
+			}
+		}
+		if i < len(h.Bounds) {
+			lower = h.Bounds[i]
+		}
+	}
+	fmt.Fprintf(&b, "  mean %v, max %v", h.Mean(), h.Max)
+	return b.String()
+}
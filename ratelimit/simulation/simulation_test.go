@@ -0,0 +1,348 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yuhanfang/riot/ratelimit"
+)
+
+// developmentEndpoint has the limits of a development API key.
+var developmentEndpoint = Endpoint{
+	AppLimits:           []Limit{{20, time.Second}, {100, 2 * time.Minute}},
+	DefaultMethodLimits: []Limit{{2000, time.Minute}},
+}
+
+func TestRunRespectsLimits(t *testing.T) {
+	e := developmentEndpoint
+	report, err := Run(Config{
+		Endpoint: &e,
+		Workers:  10,
+		Duration: 10 * time.Minute,
+		Latency:  50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	// Without a preset, the limiter learns limits from the first response. The
+	// calls that are made concurrently with the first call are not tracked,
+	// but are included in the counts that Riot reports.
+	if n := report.TotalRateLimited(); n != 0 {
+		t.Errorf("got %d 429s, want none", n)
+	}
+	if got, want := report.Calls-report.TotalRateLimited(), 500; got != want {
+		t.Errorf("got %d successful calls, want %d", got, want)
+	}
+}
+
+func TestRunSteadyState(t *testing.T) {
+	e := developmentEndpoint
+	report, err := Run(Config{
+		Endpoint: &e,
+		Workers:  1,
+		Duration: 10 * time.Minute,
+		Latency:  50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	if n := report.TotalRateLimited(); n != 0 {
+		t.Errorf("got %d 429s, want none", n)
+	}
+	if got, want := report.Calls, 500; got != want {
+		t.Errorf("got %d calls, want %d", got, want)
+	}
+}
+
+func TestRunConservativeStart(t *testing.T) {
+	e := developmentEndpoint
+	report, err := Run(Config{
+		NewLimiter: func(clock ratelimit.Clock) ratelimit.Limiter {
+			return ratelimit.NewLimiter(ratelimit.WithClock(clock), ratelimit.WithConservativeStart())
+		},
+		Endpoint: &e,
+		Workers:  10,
+		Duration: 10 * time.Minute,
+		Latency:  50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	if n := report.TotalRateLimited(); n != 0 {
+		t.Errorf("got %d 429s, want none", n)
+	}
+	if got, want := report.Calls, 500; got != want {
+		t.Errorf("got %d calls, want %d", got, want)
+	}
+}
+
+func TestRunPreset(t *testing.T) {
+	e := developmentEndpoint
+	report, err := Run(Config{
+		NewLimiter: func(clock ratelimit.Clock) ratelimit.Limiter {
+			return ratelimit.NewLimiter(ratelimit.WithClock(clock), ratelimit.WithPreset(ratelimit.DevelopmentKey))
+		},
+		Endpoint: &e,
+		Workers:  10,
+		Duration: 10 * time.Minute,
+		Latency:  50 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	if n := report.TotalRateLimited(); n != 0 {
+		t.Errorf("got %d 429s, want none", n)
+	}
+	if got, want := report.Calls, 500; got != want {
+		t.Errorf("got %d calls, want %d", got, want)
+	}
+}
+
+func TestRunSeparatesMethods(t *testing.T) {
+	e := Endpoint{
+		AppLimits: []Limit{{500, 10 * time.Second}},
+		MethodLimits: map[string][]Limit{
+			"/lol/match/v5/matches": {{250, 10 * time.Second}},
+		},
+		DefaultMethodLimits: []Limit{{50, 10 * time.Second}},
+	}
+	report, err := Run(Config{
+		Endpoint: &e,
+		Workers:  2,
+		Duration: time.Minute,
+		Invocation: func(worker, n int) ratelimit.Invocation {
+			inv := DefaultInvocation
+			if worker == 0 {
+				inv.Method = "/lol/match/v5/matches"
+			}
+			return inv
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	if n := report.TotalRateLimited(); n != 0 {
+		t.Errorf("got %d 429s, want none", n)
+	}
+	if want := 6 * 300; report.Calls != want {
+		t.Errorf("got %d calls, want %d", report.Calls, want)
+	}
+}
+
+func TestRunReportsOutage(t *testing.T) {
+	e := Endpoint{
+		AppLimits: []Limit{{500, 10 * time.Second}},
+		Outages:   []Outage{{Start: time.Minute, End: 2 * time.Minute, RetryAfter: 10 * time.Second}},
+	}
+	report, err := Run(Config{
+		Endpoint: &e,
+		Workers:  1,
+		Duration: 3 * time.Minute,
+		Latency:  10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	if report.RateLimited["service"] == 0 {
+		t.Error("outage was not reported")
+	}
+	if n := report.RateLimited["application"] + report.RateLimited["method"]; n != 0 {
+		t.Errorf("got %d application or method 429s, want none", n)
+	}
+	// The limiter backs off for Retry-After, so there is one 429 per 10
+	// seconds of outage.
+	if got, want := report.RateLimited["service"], 6; got != want {
+		t.Errorf("got %d service 429s, want %d", got, want)
+	}
+}
+
+func TestRunOutageWithoutRetryAfter(t *testing.T) {
+	e := Endpoint{
+		AppLimits: []Limit{{500, 10 * time.Second}},
+		Outages:   []Outage{{Start: 0, End: time.Minute}},
+	}
+	report, err := Run(Config{
+		Endpoint: &e,
+		Workers:  1,
+		Duration: 2 * time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	// Without Retry-After, the worker retries as soon as each 429 arrives,
+	// which takes the minimum latency.
+	if got, want := report.RateLimited["service"], int(time.Minute/minLatency); got != want {
+		t.Errorf("got %d service 429s, want %d", got, want)
+	}
+	if report.Calls == report.TotalRateLimited() {
+		t.Error("no call succeeded after the outage")
+	}
+}
+
+func TestRunReportsMisconfiguration(t *testing.T) {
+	e := developmentEndpoint
+	report, err := Run(Config{
+		// The limiter ignores the headers, like a crawler without limiting.
+		NewLimiter: func(clock ratelimit.Clock) ratelimit.Limiter {
+			return ignoreHeaders{ratelimit.NewLimiter(ratelimit.WithClock(clock))}
+		},
+		Endpoint: &e,
+		Workers:  2,
+		Duration: time.Minute,
+		Latency:  10 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Log(report)
+	if report.RateLimited["application"] == 0 {
+		t.Error("exceeding the application limit was not reported")
+	}
+	if report.Wait.Max != 0 {
+		t.Errorf("got maximum wait %v, want 0", report.Wait.Max)
+	}
+}
+
+func TestClockFiresTimersInOrder(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewClock(start)
+	var fired []string
+	fire := func(name string) func() {
+		return func() {
+			fired = append(fired, fmt.Sprintf("%s at %v", name, c.Now().Sub(start)))
+		}
+	}
+	c.AfterFunc(2*time.Second, fire("late"))
+	c.AfterFunc(time.Second, fire("early"))
+	c.AfterFunc(time.Second, fire("tied"))
+	stopped := c.AfterFunc(time.Second, fire("stopped"))
+	if !stopped.Stop() {
+		t.Error("Stop returned false for a pending timer")
+	}
+	if got := c.Pending(); got != 3 {
+		t.Errorf("got %d pending timers, want 3", got)
+	}
+
+	c.Advance(1500 * time.Millisecond)
+	if got, want := strings.Join(fired, ", "), "early at 1s, tied at 1s"; got != want {
+		t.Errorf("fired %q, want %q", got, want)
+	}
+	if got, want := c.Now().Sub(start), 1500*time.Millisecond; got != want {
+		t.Errorf("got time %v, want %v", got, want)
+	}
+
+	if !c.AdvanceToNext() {
+		t.Fatal("AdvanceToNext found no timer")
+	}
+	if got, want := fired[len(fired)-1], "late at 2s"; got != want {
+		t.Errorf("fired %q, want %q", got, want)
+	}
+	if c.AdvanceToNext() || stopped.Stop() {
+		t.Error("stopped timer is still pending")
+	}
+}
+
+func TestClockSettlesTrackedGoroutines(t *testing.T) {
+	start := time.Unix(1000, 0)
+	c := NewClock(start)
+	woken := make(chan time.Time, 2)
+	for _, d := range []time.Duration{time.Second, 2 * time.Second} {
+		wake := make(chan struct{})
+		c.AfterFunc(d, func() {
+			close(wake)
+		})
+		c.Go(context.Background(), func(ctx context.Context) {
+			c.Wait(ctx, wake)
+			woken <- c.Now()
+		})
+	}
+	c.Settle()
+	if got := c.Goroutines(); got != 2 {
+		t.Fatalf("got %d goroutines after settling, want 2", got)
+	}
+
+	// Each goroutine runs at the time its timer fires.
+	c.Advance(time.Minute)
+	for _, want := range []time.Duration{time.Second, 2 * time.Second} {
+		if got := (<-woken).Sub(start); got != want {
+			t.Errorf("woken at %v, want %v", got, want)
+		}
+	}
+	if got := c.Goroutines(); got != 0 {
+		t.Errorf("got %d goroutines, want 0", got)
+	}
+}
+
+func TestClockIgnoresUntrackedWaits(t *testing.T) {
+	c := NewClock(time.Unix(1000, 0))
+	wake := make(chan struct{})
+	waited := make(chan error, 1)
+	go func() {
+		waited <- c.Wait(context.Background(), wake)
+	}()
+
+	// A tracked goroutine is running until it receives from release, however
+	// many untracked goroutines are blocked in Wait.
+	release := make(chan struct{})
+	c.Go(context.Background(), func(ctx context.Context) {
+		<-release
+	})
+	settled := make(chan struct{})
+	go func() {
+		c.Settle()
+		close(settled)
+	}()
+	select {
+	case <-settled:
+		t.Fatal("settled while a tracked goroutine was running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-settled
+	close(wake)
+	if err := <-waited; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := NewHistogram()
+	for _, d := range []time.Duration{0, 0, 5 * time.Millisecond, 2 * time.Second, 2 * time.Minute} {
+		h.Add(d)
+	}
+	if got, want := h.Count(), 5; got != want {
+		t.Errorf("got count %d, want %d", got, want)
+	}
+	if h.Counts[0] != 2 || h.Counts[len(h.Counts)-1] != 1 {
+		t.Errorf("got counts %v", h.Counts)
+	}
+	if got, want := h.Max, 2*time.Minute; got != want {
+		t.Errorf("got max %v, want %v", got, want)
+	}
+}
+
+// ignoreHeaders is a limiter that never learns limits from responses.
+type ignoreHeaders struct {
+	ratelimit.Limiter
+}
+
+func (l ignoreHeaders) Acquire(ctx context.Context, inv ratelimit.Invocation) (ratelimit.Done, ratelimit.Cancel, error) {
+	done, cancel, err := l.Limiter.Acquire(ctx, inv)
+	if err != nil {
+		return nil, nil, err
+	}
+	return func(*http.Response) error {
+		return done(nil)
+	}, cancel, nil
+}
@@ -0,0 +1,43 @@
+// Package doertest provides fake external.Doer implementations for testing
+// clients of the Riot API without making HTTP requests.
+package doertest
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/yuhanfang/riot/external"
+)
+
+// DoerFunc adapts a function to external.Doer.
+type DoerFunc func(*http.Request) (*http.Response, error)
+
+var _ external.Doer = DoerFunc(nil)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Response returns a response to the request with the given status, headers
+// and body. A nil header is replaced with an empty one.
+func Response(req *http.Request, status int, header http.Header, body string) *http.Response {
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}
+}
+
+// StaticResponse returns a Doer that responds to every request with the given
+// status, headers and body.
+func StaticResponse(status int, header http.Header, body string) DoerFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		return Response(req, status, header, body), nil
+	}
+}
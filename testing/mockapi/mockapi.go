@@ -0,0 +1,343 @@
+// Package mockapi implements an HTTP server that imitates the Riot API.
+//
+// The server serves data from a fake.Dataset, so apiclient, ratelimit and
+// the ratelimit service can be exercised end to end without a live API key.
+// Like the Riot API, the server validates the X-Riot-Token header, reports
+// application and method rate limits and counts in the X-App-Rate-Limit,
+// X-App-Rate-Limit-Count, X-Method-Rate-Limit and X-Method-Rate-Limit-Count
+// headers, and returns HTTP 429 with Retry-After and X-Rate-Limit-Type when a
+// quota is exceeded.
+//
+// The platform or regional cluster of a request is taken from the first label
+// of the Host header, as with na1.api.riotgames.com, or from a leading path
+// segment, as with http://localhost:8080/na1/lol/summoner/v4/... Requests
+// that specify neither are served by Config.DefaultRegion. For example, the
+// following client sends every NA1 call to a local server:
+//
+//	c := apiclient.New(key, http.DefaultClient, ratelimit.NewLimiter(),
+//		apiclient.WithBaseURL(region.NA1, "http://localhost:8080/na1"))
+//
+// Use the New() constructor to initialize a server.
+package mockapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/apiclient/fake"
+	"github.com/yuhanfang/riot/constants/region"
+)
+
+// Limit is a rate limit of a number of requests per time window.
+type Limit struct {
+	Requests int
+	Window   time.Duration
+}
+
+// ParseLimits parses limits in the format of the X-App-Rate-Limit header,
+// e.g. "20:1,100:120" for 20 requests per second and 100 requests per two
+// minutes.
+func ParseLimits(s string) ([]Limit, error) {
+	var res []Limit
+	for _, piece := range strings.Split(s, ",") {
+		piece = strings.TrimSpace(piece)
+		if piece == "" {
+			continue
+		}
+		parts := strings.Split(piece, ":")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid limit %q", piece)
+		}
+		requests, err := strconv.Atoi(parts[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit %q: %v", piece, err)
+		}
+		seconds, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit %q: %v", piece, err)
+		}
+		res = append(res, Limit{Requests: requests, Window: time.Duration(seconds) * time.Second})
+	}
+	return res, nil
+}
+
+// formatLimits formats limits in the X-App-Rate-Limit header format.
+func formatLimits(limits []Limit) string {
+	pieces := make([]string, len(limits))
+	for i, l := range limits {
+		pieces[i] = fmt.Sprintf("%d:%d", l.Requests, int(l.Window/time.Second))
+	}
+	return strings.Join(pieces, ",")
+}
+
+// DefaultAppLimits are the application limits of a development key.
+var DefaultAppLimits = []Limit{
+	{Requests: 20, Window: time.Second},
+	{Requests: 100, Window: 2 * time.Minute},
+}
+
+// DefaultMethodLimits are the method limits used for routes that have no
+// limits in Config.MethodLimits.
+var DefaultMethodLimits = []Limit{
+	{Requests: 2000, Window: time.Minute},
+}
+
+// Config configures the server. The zero value accepts any API key and uses
+// the default limits.
+type Config struct {
+	// Keys are the accepted API keys. If empty, any non-empty key is accepted.
+	Keys []string
+
+	// AppLimits are the application limits, which apply per key and region.
+	// Defaults to DefaultAppLimits.
+	AppLimits []Limit
+
+	// MethodLimits maps route templates, e.g. "/lol/match/v4/matches/{matchId}",
+	// to method limits, which apply per key, region and route. See Routes for
+	// the list of templates.
+	MethodLimits map[string][]Limit
+
+	// DefaultMethodLimits are the method limits of routes that are not in
+	// MethodLimits. Defaults to DefaultMethodLimits.
+	DefaultMethodLimits []Limit
+
+	// DefaultRegion serves requests that do not specify a platform or
+	// regional cluster in the host or path. Defaults to NA1.
+	DefaultRegion region.Region
+}
+
+// window counts requests in a fixed time window, which starts with the first
+// request after the previous window expires.
+type window struct {
+	start time.Time
+	count int
+}
+
+type server struct {
+	client *fake.Client
+	config Config
+	keys   map[string]bool
+	router *mux.Router
+
+	// windows maps a quota bucket to a window per limit.
+	windows map[string][]window
+	lock    sync.Mutex
+}
+
+// New returns an http.Handler that serves the dataset according to the
+// configuration.
+func New(d *fake.Dataset, config Config) http.Handler {
+	if config.AppLimits == nil {
+		config.AppLimits = DefaultAppLimits
+	}
+	if config.DefaultMethodLimits == nil {
+		config.DefaultMethodLimits = DefaultMethodLimits
+	}
+	if config.DefaultRegion == "" {
+		config.DefaultRegion = region.NA1
+	}
+	s := &server{
+		client:  fake.New(d),
+		config:  config,
+		keys:    make(map[string]bool),
+		router:  mux.NewRouter(),
+		windows: make(map[string][]window),
+	}
+	for _, k := range config.Keys {
+		s.keys[k] = true
+	}
+	for _, rt := range routes {
+		s.router.Handle(rt.template, s.handle(rt)).Methods("GET")
+	}
+	return s
+}
+
+// target is the platform and regional cluster that serve a request.
+type target struct {
+	// name is the platform or regional cluster given in the request, which
+	// determines the quota bucket.
+	name     string
+	platform region.Region
+	routing  region.Routing
+}
+
+// resolve returns the target named by s, if any.
+func resolve(s string) (target, bool) {
+	for _, p := range region.All() {
+		if strings.EqualFold(string(p), s) {
+			return target{name: string(p), platform: p, routing: p.Routing()}, true
+		}
+	}
+	for _, r := range region.AllRoutings() {
+		if !strings.EqualFold(string(r), s) {
+			continue
+		}
+		// Regional routes are served by any platform in the cluster.
+		t := target{name: string(r), routing: r}
+		for _, p := range region.All() {
+			if p.Routing() == r {
+				t.platform = p
+				break
+			}
+		}
+		return t, true
+	}
+	return target{}, false
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if i := strings.IndexAny(host, ".:"); i >= 0 {
+		host = host[:i]
+	}
+	t, ok := resolve(host)
+	if !ok {
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+		if t, ok = resolve(parts[0]); ok {
+			r.URL.Path = "/"
+			if len(parts) > 1 {
+				r.URL.Path += parts[1]
+			}
+			r.URL.RawPath = ""
+		} else {
+			t, _ = resolve(string(s.config.DefaultRegion))
+		}
+	}
+	s.router.ServeHTTP(w, r.WithContext(withTarget(r.Context(), t)))
+}
+
+// writeStatus writes an error in the format of the Riot API.
+func writeStatus(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": map[string]interface{}{
+			"message":     message,
+			"status_code": code,
+		},
+	})
+}
+
+// authorize writes an error and returns false if the request does not have a
+// valid API key.
+func (s *server) authorize(w http.ResponseWriter, r *http.Request) bool {
+	key := r.Header.Get("X-Riot-Token")
+	if key == "" {
+		writeStatus(w, http.StatusUnauthorized, "Unauthorized")
+		return false
+	}
+	if len(s.keys) > 0 && !s.keys[key] {
+		writeStatus(w, http.StatusForbidden, "Forbidden")
+		return false
+	}
+	return true
+}
+
+// count counts a request against the limits of the bucket. It returns the
+// resulting counts, and the time until the bucket has quota if a limit is
+// exceeded. Requests that exceed a limit are not counted.
+func (s *server) count(bucket string, limits []Limit, now time.Time) ([]int, time.Duration) {
+	windows := s.windows[bucket]
+	if len(windows) != len(limits) {
+		windows = make([]window, len(limits))
+		s.windows[bucket] = windows
+	}
+	var retryAfter time.Duration
+	for i, l := range limits {
+		if now.Sub(windows[i].start) >= l.Window {
+			windows[i] = window{start: now}
+		}
+		if windows[i].count >= l.Requests {
+			if wait := windows[i].start.Add(l.Window).Sub(now); wait > retryAfter {
+				retryAfter = wait
+			}
+		}
+	}
+	counts := make([]int, len(limits))
+	for i := range limits {
+		if retryAfter == 0 {
+			windows[i].count++
+		}
+		counts[i] = windows[i].count
+	}
+	return counts, retryAfter
+}
+
+// formatCounts formats counts in the X-App-Rate-Limit-Count header format.
+func formatCounts(counts []int, limits []Limit) string {
+	pieces := make([]string, len(limits))
+	for i, l := range limits {
+		pieces[i] = fmt.Sprintf("%d:%d", counts[i], int(l.Window/time.Second))
+	}
+	return strings.Join(pieces, ",")
+}
+
+// acquire counts the request against application and method quota and sets
+// rate limit headers. It writes an error and returns false if quota is
+// exceeded.
+func (s *server) acquire(w http.ResponseWriter, r *http.Request, t target, template string) bool {
+	key := r.Header.Get("X-Riot-Token")
+	methodLimits, ok := s.config.MethodLimits[template]
+	if !ok {
+		methodLimits = s.config.DefaultMethodLimits
+	}
+
+	now := time.Now()
+	s.lock.Lock()
+	appBucket := fmt.Sprintf("%s\x00%s", key, t.name)
+	appCounts, appWait := s.count(appBucket, s.config.AppLimits, now)
+	methodCounts, methodWait := s.count(appBucket+"\x00"+template, methodLimits, now)
+	s.lock.Unlock()
+
+	h := w.Header()
+	h.Set("X-App-Rate-Limit", formatLimits(s.config.AppLimits))
+	h.Set("X-App-Rate-Limit-Count", formatCounts(appCounts, s.config.AppLimits))
+	h.Set("X-Method-Rate-Limit", formatLimits(methodLimits))
+	h.Set("X-Method-Rate-Limit-Count", formatCounts(methodCounts, methodLimits))
+
+	wait, limitType := appWait, "application"
+	if methodWait > wait {
+		wait, limitType = methodWait, "method"
+	}
+	if wait == 0 {
+		return true
+	}
+	// Retry-After is given in whole seconds, rounded up.
+	h.Set("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+	h.Set("X-Rate-Limit-Type", limitType)
+	writeStatus(w, http.StatusTooManyRequests, "Rate limit exceeded")
+	return false
+}
+
+// handle returns the handler for a route.
+func (s *server) handle(rt route) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorize(w, r) {
+			return
+		}
+		t := targetFromContext(r.Context())
+		if !s.acquire(w, r, t, rt.template) {
+			return
+		}
+		res, err := rt.serve(r.Context(), s.client, t, mux.Vars(r), r.URL.Query())
+		if err != nil {
+			var apiErr *apiclient.APIError
+			if errors.As(err, &apiErr) {
+				writeStatus(w, apiErr.StatusCode, apiErr.Message)
+				return
+			}
+			writeStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json;charset=utf-8")
+		json.NewEncoder(w).Encode(res)
+	})
+}
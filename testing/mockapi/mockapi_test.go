@@ -0,0 +1,187 @@
+package mockapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/apiclient/fake"
+	"github.com/yuhanfang/riot/constants/champion"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/ratelimit"
+)
+
+func testDataset() *fake.Dataset {
+	return &fake.Dataset{
+		Summoners: map[region.Region][]apiclient.Summoner{
+			region.NA1:  {{Name: "Waddle Chirp", ID: "na-summoner"}},
+			region.EUW1: {{Name: "Waddle Chirp", ID: "euw-summoner"}},
+		},
+		Timelines: map[region.Region]map[int64]apiclient.MatchTimeline{
+			region.NA1: {1: {Frames: []apiclient.MatchFrame{{
+				ParticipantFrames: apiclient.ParticipantFrames{
+					Frames: []apiclient.MatchParticipantFrame{{ParticipantID: 1, TotalGold: 500}},
+				},
+			}}}},
+		},
+	}
+}
+
+func TestEndToEnd(t *testing.T) {
+	ts := httptest.NewServer(New(testDataset(), Config{Keys: []string{"key"}}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	c := apiclient.New("key", http.DefaultClient, ratelimit.NewLimiter(),
+		apiclient.WithBaseURL(region.EUW1, ts.URL+"/euw1"),
+		apiclient.WithDefaultBaseURL(ts.URL))
+
+	s, err := c.GetBySummonerName(ctx, region.NA1, "waddlechirp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ID != "na-summoner" {
+		t.Errorf("got summoner %q, want na-summoner", s.ID)
+	}
+	s, err = c.GetBySummonerName(ctx, region.EUW1, "waddlechirp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.ID != "euw-summoner" {
+		t.Errorf("got summoner %q, want euw-summoner", s.ID)
+	}
+
+	timeline, err := c.GetMatchTimeline(ctx, region.NA1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := timeline.Frames[0].ParticipantFrames.Frames[0].TotalGold; got != 500 {
+		t.Errorf("got total gold %d, want 500", got)
+	}
+
+	_, err = c.GetBySummonerID(ctx, region.NA1, "missing")
+	if !errors.Is(err, apiclient.ErrDataNotFound) {
+		t.Errorf("got %v, want ErrDataNotFound", err)
+	}
+
+	bad := apiclient.New("bad", http.DefaultClient, ratelimit.NewLimiter(), apiclient.WithDefaultBaseURL(ts.URL))
+	_, err = bad.GetBySummonerID(ctx, region.NA1, "na-summoner")
+	if !errors.Is(err, apiclient.ErrForbidden) {
+		t.Errorf("got %v, want ErrForbidden", err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	ts := httptest.NewServer(New(testDataset(), Config{
+		AppLimits: []Limit{{Requests: 3, Window: 10 * time.Second}},
+		MethodLimits: map[string][]Limit{
+			"/lol/summoner/v4/summoners/{summonerId}": {{Requests: 1, Window: 5 * time.Second}},
+		},
+	}))
+	defer ts.Close()
+
+	get := func(path string) *http.Response {
+		req, err := http.NewRequest("GET", ts.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("X-Riot-Token", "key")
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res
+	}
+
+	res := get("/lol/summoner/v4/summoners/na-summoner")
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("got HTTP %d, want 200", res.StatusCode)
+	}
+	if got := res.Header.Get("X-App-Rate-Limit"); got != "3:10" {
+		t.Errorf("got app limit %q, want 3:10", got)
+	}
+	if got := res.Header.Get("X-App-Rate-Limit-Count"); got != "1:10" {
+		t.Errorf("got app count %q, want 1:10", got)
+	}
+	if got := res.Header.Get("X-Method-Rate-Limit-Count"); got != "1:5" {
+		t.Errorf("got method count %q, want 1:5", got)
+	}
+
+	res = get("/lol/summoner/v4/summoners/na-summoner")
+	if res.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("got HTTP %d, want 429", res.StatusCode)
+	}
+	if res.Header.Get("X-Rate-Limit-Type") != "method" || res.Header.Get("Retry-After") != "5" {
+		t.Errorf("got type %q and Retry-After %q, want method and 5", res.Header.Get("X-Rate-Limit-Type"), res.Header.Get("Retry-After"))
+	}
+
+	// Other platforms have separate quota.
+	if res = get("/euw1/lol/summoner/v4/summoners/euw-summoner"); res.StatusCode != http.StatusOK {
+		t.Errorf("got HTTP %d for EUW1, want 200", res.StatusCode)
+	}
+
+	get("/lol/summoner/v4/summoners/by-name/waddlechirp")
+	get("/lol/summoner/v4/summoners/by-name/waddlechirp")
+	res = get("/lol/summoner/v4/summoners/by-name/waddlechirp")
+	if res.StatusCode != http.StatusTooManyRequests || res.Header.Get("X-Rate-Limit-Type") != "application" {
+		t.Errorf("got HTTP %d with type %q, want 429 with application", res.StatusCode, res.Header.Get("X-Rate-Limit-Type"))
+	}
+}
+
+func TestMethodsWithoutArguments(t *testing.T) {
+	ts := httptest.NewServer(New(&fake.Dataset{
+		FeaturedGames: map[region.Region]apiclient.FeaturedGames{
+			region.NA1: {ClientRefreshInterval: 300, GameList: []apiclient.FeaturedGameInfoDTO{{GameId: 1}}},
+		},
+		ChampionRotations: map[region.Region]apiclient.ChampionRotation{
+			region.NA1: {FreeChampionIDs: []champion.Champion{champion.Ahri}, MaxNewPlayerLevel: 10},
+		},
+		PlatformStatus: map[region.Region]apiclient.PlatformStatus{
+			region.NA1: {ID: "NA1", Name: "North America"},
+		},
+		ClashTournaments: map[region.Region][]apiclient.ClashTournament{
+			region.NA1: {{ID: 2001, NameKey: "bilgewater"}},
+		},
+	}, Config{}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	c := apiclient.New("key", http.DefaultClient, ratelimit.NewLimiter(), apiclient.WithDefaultBaseURL(ts.URL))
+
+	games, err := c.GetFeaturedGames(ctx, region.NA1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if games.ClientRefreshInterval != 300 || len(games.GameList) != 1 {
+		t.Errorf("got featured games %+v", games)
+	}
+
+	rotation, err := c.GetChampionRotations(ctx, region.NA1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rotation.FreeChampionIDs) != 1 || rotation.FreeChampionIDs[0] != champion.Ahri || rotation.MaxNewPlayerLevel != 10 {
+		t.Errorf("got rotation %+v", rotation)
+	}
+
+	status, err := c.GetPlatformStatus(ctx, region.NA1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Name != "North America" {
+		t.Errorf("got status %+v", status)
+	}
+
+	tournaments, err := c.GetClashTournaments(ctx, region.NA1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tournaments) != 1 || tournaments[0].ID != 2001 {
+		t.Errorf("got tournaments %+v", tournaments)
+	}
+}
@@ -0,0 +1,439 @@
+package mockapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/apiclient/fake"
+	"github.com/yuhanfang/riot/constants/challenge"
+	"github.com/yuhanfang/riot/constants/champion"
+	"github.com/yuhanfang/riot/constants/division"
+	"github.com/yuhanfang/riot/constants/game"
+	"github.com/yuhanfang/riot/constants/matchtype"
+	"github.com/yuhanfang/riot/constants/queue"
+	"github.com/yuhanfang/riot/constants/season"
+	"github.com/yuhanfang/riot/constants/tier"
+)
+
+type contextKey int
+
+const targetKey contextKey = 0
+
+func withTarget(ctx context.Context, t target) context.Context {
+	return context.WithValue(ctx, targetKey, t)
+}
+
+func targetFromContext(ctx context.Context) target {
+	t, _ := ctx.Value(targetKey).(target)
+	return t
+}
+
+// route is an API method served by the server. Every route has its own method
+// quota.
+type route struct {
+	template string
+	serve    func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error)
+}
+
+// Routes returns the templates of all routes served by the server, which are
+// the keys of Config.MethodLimits.
+func Routes() []string {
+	res := make([]string, len(routes))
+	for i, rt := range routes {
+		res[i] = rt.template
+	}
+	return res
+}
+
+// routes are registered in order, so that specific templates take precedence
+// over templates with a variable in the same position.
+var routes = []route{
+	// Account.
+	{"/riot/account/v1/accounts/by-puuid/{puuid}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetAccountByPUUID(ctx, t.routing, vars["puuid"])
+	}},
+	{"/riot/account/v1/accounts/by-riot-id/{gameName}/{tagLine}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetAccountByRiotID(ctx, t.routing, vars["gameName"], vars["tagLine"])
+	}},
+	{"/riot/account/v1/active-shards/by-game/{game}/by-puuid/{puuid}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetActiveShard(ctx, t.routing, game.Game(vars["game"]), vars["puuid"])
+	}},
+
+	// Challenges.
+	{"/lol/challenges/v1/challenges/config", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetChallengeConfigs(ctx, t.platform)
+	}},
+	{"/lol/challenges/v1/challenges/percentiles", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetChallengePercentiles(ctx, t.platform)
+	}},
+	{"/lol/challenges/v1/challenges/{challengeId}/config", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		id, err := strconv.ParseInt(vars["challengeId"], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetChallengeConfig(ctx, t.platform, id)
+	}},
+	{"/lol/challenges/v1/challenges/{challengeId}/percentiles", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		id, err := strconv.ParseInt(vars["challengeId"], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetChallengePercentilesByID(ctx, t.platform, id)
+	}},
+	{"/lol/challenges/v1/challenges/{challengeId}/leaderboards/by-level/{level}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		id, err := strconv.ParseInt(vars["challengeId"], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		limit, err := parseInt(q, "limit")
+		if err != nil {
+			return nil, err
+		}
+		if limit == nil {
+			limit = new(int)
+		}
+		return c.GetChallengeLeaderboard(ctx, t.platform, id, challenge.Level(vars["level"]), *limit)
+	}},
+	{"/lol/challenges/v1/player-data/{puuid}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetPlayerChallenges(ctx, t.platform, vars["puuid"])
+	}},
+
+	// Champion mastery.
+	{"/lol/champion-mastery/v4/champion-masteries/by-summoner/{summonerId}/by-champion/{championId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		champ, err := strconv.Atoi(vars["championId"])
+		if err != nil {
+			return nil, err
+		}
+		return c.GetChampionMastery(ctx, t.platform, vars["summonerId"], champion.Champion(champ))
+	}},
+	{"/lol/champion-mastery/v4/champion-masteries/by-summoner/{summonerId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetAllChampionMasteries(ctx, t.platform, vars["summonerId"])
+	}},
+	{"/lol/champion-mastery/v4/scores/by-summoner/{summonerId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetChampionMasteryScore(ctx, t.platform, vars["summonerId"])
+	}},
+	{"/lol/champion-mastery/v4/champion-masteries/by-puuid/{puuid}/by-champion/{championId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		champ, err := strconv.Atoi(vars["championId"])
+		if err != nil {
+			return nil, err
+		}
+		return c.GetChampionMasteryByPUUID(ctx, t.platform, vars["puuid"], champion.Champion(champ))
+	}},
+	{"/lol/champion-mastery/v4/champion-masteries/by-puuid/{puuid}/top", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		count, err := parseInt(q, "count")
+		if err != nil {
+			return nil, err
+		}
+		if count == nil {
+			return c.GetTopChampionMasteries(ctx, t.platform, vars["puuid"], 0)
+		}
+		return c.GetTopChampionMasteries(ctx, t.platform, vars["puuid"], *count)
+	}},
+	{"/lol/champion-mastery/v4/champion-masteries/by-puuid/{puuid}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetAllChampionMasteriesByPUUID(ctx, t.platform, vars["puuid"])
+	}},
+	{"/lol/champion-mastery/v4/scores/by-puuid/{puuid}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetChampionMasteryScoreByPUUID(ctx, t.platform, vars["puuid"])
+	}},
+
+	// Champions.
+	{"/lol/platform/v3/champions/{id}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		champ, err := strconv.Atoi(vars["id"])
+		if err != nil {
+			return nil, err
+		}
+		return c.GetChampionByID(ctx, t.platform, champion.Champion(champ))
+	}},
+	{"/lol/platform/v3/champions", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetChampions(ctx, t.platform)
+	}},
+	{"/lol/platform/v3/champion-rotations", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetChampionRotations(ctx, t.platform)
+	}},
+
+	// Clash.
+	{"/lol/clash/v1/players/by-summoner/{summonerId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetClashPlayersBySummoner(ctx, t.platform, vars["summonerId"])
+	}},
+	{"/lol/clash/v1/players/by-puuid/{puuid}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetClashPlayersByPUUID(ctx, t.platform, vars["puuid"])
+	}},
+	{"/lol/clash/v1/teams/{teamId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetClashTeam(ctx, t.platform, vars["teamId"])
+	}},
+	{"/lol/clash/v1/tournaments/by-team/{teamId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetClashTournamentByTeam(ctx, t.platform, vars["teamId"])
+	}},
+	{"/lol/clash/v1/tournaments/{tournamentId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		id, err := strconv.Atoi(vars["tournamentId"])
+		if err != nil {
+			return nil, err
+		}
+		return c.GetClashTournament(ctx, t.platform, id)
+	}},
+	{"/lol/clash/v1/tournaments", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetClashTournaments(ctx, t.platform)
+	}},
+
+	// League.
+	{"/lol/league/v4/challengerleagues/by-queue/{queue}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		qu, err := parseQueue(vars["queue"])
+		if err != nil {
+			return nil, err
+		}
+		return c.GetChallengerLeague(ctx, t.platform, qu)
+	}},
+	{"/lol/league/v4/grandmasterleagues/by-queue/{queue}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		qu, err := parseQueue(vars["queue"])
+		if err != nil {
+			return nil, err
+		}
+		return c.GetGrandmasterLeague(ctx, t.platform, qu)
+	}},
+	{"/lol/league/v4/masterleagues/by-queue/{queue}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		qu, err := parseQueue(vars["queue"])
+		if err != nil {
+			return nil, err
+		}
+		return c.GetMasterLeague(ctx, t.platform, qu)
+	}},
+	{"/lol/league/v4/leagues/{leagueId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetLeagueByID(ctx, t.platform, vars["leagueId"])
+	}},
+	{"/lol/league/v4/entries/by-summoner/{summonerId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetAllLeaguePositionsForSummoner(ctx, t.platform, vars["summonerId"])
+	}},
+	{"/lol/league/v4/entries/{queue}/{tier}/{division}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		qu, err := parseQueue(vars["queue"])
+		if err != nil {
+			return nil, err
+		}
+		page, err := parsePage(q)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetLeagueEntries(ctx, t.platform, qu, tier.Tier(vars["tier"]), division.Division(vars["division"]), page)
+	}},
+	{"/lol/league-exp/v4/entries/{queue}/{tier}/{division}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		qu, err := parseQueue(vars["queue"])
+		if err != nil {
+			return nil, err
+		}
+		page, err := parsePage(q)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetLeagueExpEntries(ctx, t.platform, qu, tier.Tier(vars["tier"]), division.Division(vars["division"]), page)
+	}},
+
+	// Match-v4.
+	{"/lol/match/v4/matches/{matchId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		id, err := strconv.ParseInt(vars["matchId"], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetMatch(ctx, t.platform, id)
+	}},
+	{"/lol/match/v4/matchlists/by-account/{accountId}/recent", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetRecentMatchlist(ctx, t.platform, vars["accountId"])
+	}},
+	{"/lol/match/v4/matchlists/by-account/{accountId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		opts, err := parseMatchlistOptions(q)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetMatchlist(ctx, t.platform, vars["accountId"], opts)
+	}},
+	{"/lol/match/v4/timelines/by-match/{matchId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		id, err := strconv.ParseInt(vars["matchId"], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetMatchTimeline(ctx, t.platform, id)
+	}},
+
+	// Match-v5.
+	{"/lol/match/v5/matches/by-puuid/{puuid}/ids", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		opts, err := parseMatchIDsOptions(q)
+		if err != nil {
+			return nil, err
+		}
+		return c.GetMatchIDsByPUUID(ctx, t.platform, vars["puuid"], opts)
+	}},
+	{"/lol/match/v5/matches/{matchId}/timeline", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetMatchTimelineV5(ctx, t.platform, vars["matchId"])
+	}},
+	{"/lol/match/v5/matches/{matchId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetMatchV5(ctx, t.platform, vars["matchId"])
+	}},
+
+	// Spectator.
+	{"/lol/spectator/v4/active-games/by-summoner/{summonerId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetCurrentGameInfoBySummoner(ctx, t.platform, vars["summonerId"])
+	}},
+	{"/lol/spectator/v4/featured-games", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetFeaturedGames(ctx, t.platform)
+	}},
+	{"/lol/spectator/v5/active-games/by-summoner/{puuid}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetCurrentGameInfoByPUUID(ctx, t.platform, vars["puuid"])
+	}},
+	{"/lol/spectator/v5/featured-games", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetFeaturedGamesV5(ctx, t.platform)
+	}},
+
+	// Status.
+	{"/lol/status/v4/platform-data", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetPlatformStatus(ctx, t.platform)
+	}},
+
+	// Summoner.
+	{"/lol/summoner/v4/summoners/by-account/{accountId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetByAccountID(ctx, t.platform, vars["accountId"])
+	}},
+	{"/lol/summoner/v4/summoners/by-name/{summonerName}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetBySummonerName(ctx, t.platform, vars["summonerName"])
+	}},
+	{"/lol/summoner/v4/summoners/by-puuid/{puuid}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetBySummonerPUUID(ctx, t.platform, vars["puuid"])
+	}},
+	{"/lol/summoner/v4/summoners/{summonerId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetBySummonerID(ctx, t.platform, vars["summonerId"])
+	}},
+
+	// Third party code.
+	{"/lol/platform/v4/third-party-code/by-summoner/{summonerId}", func(ctx context.Context, c *fake.Client, t target, vars map[string]string, q url.Values) (interface{}, error) {
+		return c.GetThirdPartyCodeByID(ctx, t.platform, vars["summonerId"])
+	}},
+}
+
+// parseQueue parses a queue name such as RANKED_SOLO_5x5.
+func parseQueue(s string) (queue.Queue, error) {
+	var q queue.Queue
+	err := json.Unmarshal([]byte(strconv.Quote(s)), &q)
+	return q, err
+}
+
+// parseInts parses every value of the query parameter as an integer.
+func parseInts(q url.Values, key string) ([]int, error) {
+	var res []int
+	for _, v := range q[key] {
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, i)
+	}
+	return res, nil
+}
+
+// parseInt parses the query parameter as an integer, if present.
+func parseInt(q url.Values, key string) (*int, error) {
+	if q.Get(key) == "" {
+		return nil, nil
+	}
+	i, err := strconv.Atoi(q.Get(key))
+	if err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// parsePage parses the page query parameter, which defaults to 1.
+func parsePage(q url.Values) (int, error) {
+	page, err := parseInt(q, "page")
+	if err != nil || page == nil {
+		return 1, err
+	}
+	return *page, nil
+}
+
+// parseTime parses the query parameter as a timestamp in the given unit, if
+// present.
+func parseTime(q url.Values, key string, unit time.Duration) (*time.Time, error) {
+	if q.Get(key) == "" {
+		return nil, nil
+	}
+	i, err := strconv.ParseInt(q.Get(key), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	t := time.Unix(0, i*int64(unit))
+	return &t, nil
+}
+
+func parseMatchlistOptions(q url.Values) (*apiclient.GetMatchlistOptions, error) {
+	var (
+		opts apiclient.GetMatchlistOptions
+		err  error
+	)
+	queues, err := parseInts(q, "queue")
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range queues {
+		opts.Queue = append(opts.Queue, queue.Queue(v))
+	}
+	seasons, err := parseInts(q, "season")
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range seasons {
+		opts.Season = append(opts.Season, season.Season(v))
+	}
+	champions, err := parseInts(q, "champion")
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range champions {
+		opts.Champion = append(opts.Champion, champion.Champion(v))
+	}
+	if opts.BeginTime, err = parseTime(q, "beginTime", time.Millisecond); err != nil {
+		return nil, err
+	}
+	if opts.EndTime, err = parseTime(q, "endTime", time.Millisecond); err != nil {
+		return nil, err
+	}
+	if opts.BeginIndex, err = parseInt(q, "beginIndex"); err != nil {
+		return nil, err
+	}
+	if opts.EndIndex, err = parseInt(q, "endIndex"); err != nil {
+		return nil, err
+	}
+	return &opts, nil
+}
+
+func parseMatchIDsOptions(q url.Values) (*apiclient.GetMatchIDsOptions, error) {
+	var (
+		opts apiclient.GetMatchIDsOptions
+		err  error
+	)
+	if q.Get("queue") != "" {
+		i, err := strconv.Atoi(q.Get("queue"))
+		if err != nil {
+			return nil, err
+		}
+		qu := queue.Queue(i)
+		opts.Queue = &qu
+	}
+	if q.Get("type") != "" {
+		typ := matchtype.Type(q.Get("type"))
+		opts.Type = &typ
+	}
+	if opts.StartTime, err = parseTime(q, "startTime", time.Second); err != nil {
+		return nil, err
+	}
+	if opts.EndTime, err = parseTime(q, "endTime", time.Second); err != nil {
+		return nil, err
+	}
+	if opts.Start, err = parseInt(q, "start"); err != nil {
+		return nil, err
+	}
+	if opts.Count, err = parseInt(q, "count"); err != nil {
+		return nil, err
+	}
+	return &opts, nil
+}
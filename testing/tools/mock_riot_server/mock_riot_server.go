@@ -0,0 +1,84 @@
+// Launches a mock Riot API server on the specified port. See documentation in
+// github.com/yuhanfang/riot/testing/mockapi for details on the server, and
+// github.com/yuhanfang/riot/apiclient/fake for the fixture format. Every
+// .json file in the fixture directories is loaded as a fake.Dataset.
+//
+// Usage example:
+//
+//	mock_riot_server --port=8080 --fixtures=testdata/a,testdata/b --keys=RGAPI-test \
+//	  --app_limit=20:1,100:120 --method_limit=/lol/match/v4/matches/{matchId}=500:10
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/yuhanfang/riot/apiclient/fake"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/testing/mockapi"
+)
+
+var (
+	port          = flag.Int("port", 8080, "server port")
+	fixtures      = flag.String("fixtures", "", "comma-separated fixture directories")
+	keys          = flag.String("keys", "", "comma-separated accepted API keys; accepts any key if empty")
+	appLimit      = flag.String("app_limit", "20:1,100:120", "application rate limit")
+	defaultRegion = flag.String("default_region", region.NA1, "region of requests that do not specify one")
+	methodLimits  = make(methodLimitFlag)
+)
+
+// methodLimitFlag collects --method_limit=ROUTE=LIMITS flags.
+type methodLimitFlag map[string][]mockapi.Limit
+
+func (f methodLimitFlag) String() string {
+	return fmt.Sprint(map[string][]mockapi.Limit(f))
+}
+
+func (f methodLimitFlag) Set(s string) error {
+	i := strings.LastIndex(s, "=")
+	if i < 0 {
+		return fmt.Errorf("method limit %q must have the form ROUTE=LIMITS", s)
+	}
+	limits, err := mockapi.ParseLimits(s[i+1:])
+	if err != nil {
+		return err
+	}
+	f[s[:i]] = limits
+	return nil
+}
+
+func splitList(s string) []string {
+	var res []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func main() {
+	flag.Var(methodLimits, "method_limit", "method rate limit of a route, e.g. /lol/match/v4/matches/{matchId}=500:10; may be repeated")
+	flag.Parse()
+
+	d, err := fake.LoadDataset(splitList(*fixtures)...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	limits, err := mockapi.ParseLimits(*appLimit)
+	if err != nil {
+		log.Fatal(err)
+	}
+	config := mockapi.Config{
+		Keys:          splitList(*keys),
+		AppLimits:     limits,
+		MethodLimits:  methodLimits,
+		DefaultRegion: region.Region(strings.ToUpper(*defaultRegion)),
+	}
+	http.Handle("/", mockapi.New(d, config))
+	log.Println("listening on port", *port)
+	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", *port), nil))
+}
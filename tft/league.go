@@ -0,0 +1,131 @@
+package tft
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/constants/division"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/constants/tier"
+)
+
+// Queue is a TFT ranked queue.
+type Queue string
+
+const (
+	// QueueRanked is the standard ranked queue.
+	QueueRanked Queue = "RANKED_TFT"
+
+	// QueueHyperRoll is the hyper roll queue, which is ranked on a rated
+	// ladder instead of by tier and division.
+	QueueHyperRoll Queue = "RANKED_TFT_TURBO"
+
+	// QueueDoubleUp is the ranked double up queue.
+	QueueDoubleUp Queue = "RANKED_TFT_DOUBLE_UP"
+)
+
+type LeagueList struct {
+	LeagueID string                 `json:"leagueId"`
+	Tier     tier.Tier              `json:"tier"`
+	Entries  []apiclient.LeagueItem `json:"entries"`
+	Queue    Queue                  `json:"queue"`
+	Name     string                 `json:"name"`
+}
+
+// LeagueEntry is a summoner's standing in a queue. Standard queues are ranked
+// by tier, division and league points, while hyper roll is ranked by rated
+// tier and rating.
+type LeagueEntry struct {
+	LeagueID     string               `json:"leagueId"` // Empty for hyper roll.
+	SummonerID   string               `json:"summonerId"`
+	PUUID        string               `json:"puuid"`
+	QueueType    Queue                `json:"queueType"`
+	Tier         tier.Tier            `json:"tier"`
+	Rank         division.Division    `json:"rank"`
+	LeaguePoints int                  `json:"leaguePoints"`
+	Wins         int                  `json:"wins"` // First place finishes.
+	Losses       int                  `json:"losses"`
+	HotStreak    bool                 `json:"hotStreak"`
+	Veteran      bool                 `json:"veteran"`
+	FreshBlood   bool                 `json:"freshBlood"`
+	Inactive     bool                 `json:"inactive"`
+	MiniSeries   apiclient.MiniSeries `json:"miniSeries"`
+	RatedTier    string               `json:"ratedTier"`   // Hyper roll only. One of ORANGE, PURPLE, BLUE, GREEN or GRAY.
+	RatedRating  int                  `json:"ratedRating"` // Hyper roll only.
+}
+
+// TopRatedLadderEntry is a summoner's standing on a rated ladder.
+type TopRatedLadderEntry struct {
+	SummonerID                   string `json:"summonerId"`
+	RatedTier                    string `json:"ratedTier"`
+	RatedRating                  int    `json:"ratedRating"`
+	Wins                         int    `json:"wins"`
+	PreviousUpdateLadderPosition int    `json:"previousUpdateLadderPosition"`
+}
+
+// queueValues returns the query for the given queue, or nil if the queue is
+// empty, in which case the API defaults to QueueRanked.
+func queueValues(q Queue) url.Values {
+	if q == "" {
+		return nil
+	}
+	return url.Values{"queue": []string{string(q)}}
+}
+
+func (c *client) GetChallengerLeague(ctx context.Context, r region.Region, q Queue) (*LeagueList, error) {
+	var res LeagueList
+	err := c.d.Get(ctx, r, "/tft/league/v1/challenger", "", queueValues(q), "", &res)
+	return &res, err
+}
+
+func (c *client) GetGrandmasterLeague(ctx context.Context, r region.Region, q Queue) (*LeagueList, error) {
+	var res LeagueList
+	err := c.d.Get(ctx, r, "/tft/league/v1/grandmaster", "", queueValues(q), "", &res)
+	return &res, err
+}
+
+func (c *client) GetMasterLeague(ctx context.Context, r region.Region, q Queue) (*LeagueList, error) {
+	var res LeagueList
+	err := c.d.Get(ctx, r, "/tft/league/v1/master", "", queueValues(q), "", &res)
+	return &res, err
+}
+
+func (c *client) GetLeagueByID(ctx context.Context, r region.Region, leagueID string) (*LeagueList, error) {
+	var res LeagueList
+	err := c.d.Get(ctx, r, "/tft/league/v1/leagues", fmt.Sprintf("/%s", leagueID), nil, "", &res)
+	return &res, err
+}
+
+func (c *client) GetLeagueEntriesBySummoner(ctx context.Context, r region.Region, summonerID string) ([]LeagueEntry, error) {
+	var res []LeagueEntry
+	err := c.d.Get(ctx, r, "/tft/league/v1/entries/by-summoner", fmt.Sprintf("/%s", summonerID), nil, "", &res)
+	return res, err
+}
+
+func (c *client) GetLeagueEntriesByPUUID(ctx context.Context, r region.Region, puuid string) ([]LeagueEntry, error) {
+	var res []LeagueEntry
+	err := c.d.Get(ctx, r, "/tft/league/v1/entries/by-puuid", fmt.Sprintf("/%s", puuid), nil, "", &res)
+	return res, err
+}
+
+func (c *client) GetLeagueEntries(ctx context.Context, r region.Region, q Queue, t tier.Tier, d division.Division, page int) ([]LeagueEntry, error) {
+	if page < 1 {
+		page = 1
+	}
+	vals := queueValues(q)
+	if vals == nil {
+		vals = make(url.Values)
+	}
+	vals.Set("page", fmt.Sprintf("%d", page))
+	var res []LeagueEntry
+	err := c.d.Get(ctx, r, "/tft/league/v1/entries", fmt.Sprintf("/%s/%s", t, d), vals, "", &res)
+	return res, err
+}
+
+func (c *client) GetTopRatedLadder(ctx context.Context, r region.Region, q Queue) ([]TopRatedLadderEntry, error) {
+	var res []TopRatedLadderEntry
+	err := c.d.Get(ctx, r, "/tft/league/v1/rated-ladders", fmt.Sprintf("/%s/top", q), nil, "", &res)
+	return res, err
+}
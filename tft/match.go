@@ -0,0 +1,118 @@
+package tft
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/types"
+)
+
+type Match struct {
+	Metadata MatchMetadata `json:"metadata"`
+	Info     MatchInfo     `json:"info"`
+}
+
+type MatchMetadata struct {
+	DataVersion  string   `json:"data_version"`
+	MatchID      string   `json:"match_id"`
+	Participants []string `json:"participants"` // PUUIDs of the participants.
+}
+
+type MatchInfo struct {
+	GameDatetime    types.Milliseconds `json:"game_datetime"` // Time that the game ended.
+	GameLength      float64            `json:"game_length"`   // Game length in seconds.
+	GameVersion     string             `json:"game_version"`
+	QueueID         int                `json:"queue_id"`
+	GameType        string             `json:"tft_game_type"` // e.g. "standard", "turbo" or "pairs".
+	SetNumber       int                `json:"tft_set_number"`
+	SetCoreName     string             `json:"tft_set_core_name"`
+	Participants    []Participant      `json:"participants"`
+	EndOfGameResult string             `json:"endOfGameResult"`
+}
+
+// Participant is a player's final board and result.
+type Participant struct {
+	PUUID                string    `json:"puuid"`
+	Placement            int       `json:"placement"` // Final placement, from 1 to 8.
+	Level                int       `json:"level"`
+	LastRound            int       `json:"last_round"`
+	GoldLeft             int       `json:"gold_left"`
+	PlayersEliminated    int       `json:"players_eliminated"`
+	TimeEliminated       float64   `json:"time_eliminated"` // Seconds into the game.
+	TotalDamageToPlayers int       `json:"total_damage_to_players"`
+	Augments             []string  `json:"augments"`
+	Traits               []Trait   `json:"traits"`
+	Units                []Unit    `json:"units"`
+	Companion            Companion `json:"companion"`
+	PartnerGroupID       int       `json:"partner_group_id"` // Double up only.
+}
+
+// Trait is a trait that was active or partially active on the final board.
+type Trait struct {
+	Name        string `json:"name"`
+	NumUnits    int    `json:"num_units"`
+	Style       int    `json:"style"` // 0 is inactive, followed by bronze, silver, gold and chromatic.
+	TierCurrent int    `json:"tier_current"`
+	TierTotal   int    `json:"tier_total"`
+}
+
+// Unit is a champion on the final board.
+type Unit struct {
+	CharacterID string   `json:"character_id"`
+	Name        string   `json:"name"`
+	Rarity      int      `json:"rarity"` // Cost of the unit minus one.
+	Tier        int      `json:"tier"`   // Star level.
+	ItemNames   []string `json:"itemNames"`
+}
+
+// Companion is the player's Little Legend.
+type Companion struct {
+	ContentID string `json:"content_ID"`
+	ItemID    int    `json:"item_ID"`
+	SkinID    int    `json:"skin_ID"`
+	Species   string `json:"species"`
+}
+
+// GetMatchIDsOptions provides filtering options for GetMatchIDsByPUUID. The
+// zero value means that the option will not be used in filtering.
+type GetMatchIDsOptions struct {
+	StartTime *time.Time `json:"startTime"`
+	EndTime   *time.Time `json:"endTime"`
+	Start     *int       `json:"start"` // Start index, defaults to 0.
+	Count     *int       `json:"count"` // Number of match IDs to return. Defaults to 20.
+}
+
+func (c *client) GetMatchIDsByPUUID(ctx context.Context, r region.Region, puuid string, opts *GetMatchIDsOptions) ([]string, error) {
+	var (
+		res  []string
+		vals url.Values
+	)
+
+	if opts != nil {
+		vals = url.Values(make(map[string][]string))
+		// Times are given in epoch seconds.
+		if opts.StartTime != nil {
+			vals.Add("startTime", fmt.Sprintf("%d", opts.StartTime.Unix()))
+		}
+		if opts.EndTime != nil {
+			vals.Add("endTime", fmt.Sprintf("%d", opts.EndTime.Unix()))
+		}
+		if opts.Start != nil {
+			vals.Add("start", fmt.Sprintf("%d", *opts.Start))
+		}
+		if opts.Count != nil {
+			vals.Add("count", fmt.Sprintf("%d", *opts.Count))
+		}
+	}
+	err := c.d.GetRouting(ctx, r.Routing(), "/tft/match/v1/matches/by-puuid", fmt.Sprintf("/%s/ids", puuid), vals, "", &res)
+	return res, err
+}
+
+func (c *client) GetMatch(ctx context.Context, r region.Region, matchID string) (*Match, error) {
+	var res Match
+	err := c.d.GetRouting(ctx, r.Routing(), "/tft/match/v1/matches", fmt.Sprintf("/%s", matchID), nil, "", &res)
+	return &res, err
+}
@@ -0,0 +1,27 @@
+package tft
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/constants/region"
+)
+
+func (c *client) GetBySummonerPUUID(ctx context.Context, r region.Region, puuid string) (*apiclient.Summoner, error) {
+	var res apiclient.Summoner
+	err := c.d.Get(ctx, r, "/tft/summoner/v1/summoners/by-puuid", fmt.Sprintf("/%s", puuid), nil, "", &res)
+	return &res, err
+}
+
+func (c *client) GetByAccountID(ctx context.Context, r region.Region, accountID string) (*apiclient.Summoner, error) {
+	var res apiclient.Summoner
+	err := c.d.Get(ctx, r, "/tft/summoner/v1/summoners/by-account", fmt.Sprintf("/%s", accountID), nil, "", &res)
+	return &res, err
+}
+
+func (c *client) GetBySummonerID(ctx context.Context, r region.Region, summonerID string) (*apiclient.Summoner, error) {
+	var res apiclient.Summoner
+	err := c.d.Get(ctx, r, "/tft/summoner/v1/summoners", fmt.Sprintf("/%s", summonerID), nil, "", &res)
+	return &res, err
+}
@@ -0,0 +1,86 @@
+// Package tft accesses the Teamfight Tactics endpoints of the official Riot
+// API.
+//
+// Construct a client with the New() function, and call the various client
+// methods to retrieve data from the API. The client shares its plumbing with
+// the apiclient package, so it accepts the same options, returns the same
+// errors, and may share a ratelimit.Limiter with an apiclient.Client. TFT
+// methods have their own "/tft/..." method paths, so their quota buckets are
+// separate from those of League of Legends methods.
+package tft
+
+import (
+	"context"
+
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/constants/division"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/constants/tier"
+	"github.com/yuhanfang/riot/external"
+	"github.com/yuhanfang/riot/ratelimit"
+)
+
+// Client accesses the TFT API. Use New() to retrieve a valid instance.
+type Client interface {
+	// ----- League API -----
+
+	// GetChallengerLeague returns the challenger league of the queue.
+	GetChallengerLeague(ctx context.Context, r region.Region, q Queue) (*LeagueList, error)
+
+	// GetGrandmasterLeague returns the grandmaster league of the queue.
+	GetGrandmasterLeague(ctx context.Context, r region.Region, q Queue) (*LeagueList, error)
+
+	// GetMasterLeague returns the master league of the queue.
+	GetMasterLeague(ctx context.Context, r region.Region, q Queue) (*LeagueList, error)
+
+	// GetLeagueByID returns the league with the given ID.
+	GetLeagueByID(ctx context.Context, r region.Region, leagueID string) (*LeagueList, error)
+
+	// GetLeagueEntriesBySummoner returns the league entries of the summoner in
+	// every TFT queue, including hyper roll.
+	GetLeagueEntriesBySummoner(ctx context.Context, r region.Region, summonerID string) ([]LeagueEntry, error)
+
+	// GetLeagueEntriesByPUUID returns the league entries of the PUUID in every
+	// TFT queue, including hyper roll.
+	GetLeagueEntriesByPUUID(ctx context.Context, r region.Region, puuid string) ([]LeagueEntry, error)
+
+	// GetLeagueEntries returns a page of league entries in the given queue,
+	// tier and division. Pages start at 1.
+	GetLeagueEntries(ctx context.Context, r region.Region, q Queue, t tier.Tier, d division.Division, page int) ([]LeagueEntry, error)
+
+	// GetTopRatedLadder returns the top of a rated ladder, such as the hyper
+	// roll ladder of QueueHyperRoll.
+	GetTopRatedLadder(ctx context.Context, r region.Region, q Queue) ([]TopRatedLadderEntry, error)
+
+	// ----- Match API -----
+
+	// GetMatchIDsByPUUID returns the IDs of the TFT matches played by the
+	// PUUID, newest first.
+	GetMatchIDsByPUUID(ctx context.Context, r region.Region, puuid string, opts *GetMatchIDsOptions) ([]string, error)
+
+	// GetMatch returns the TFT match with the given ID, e.g. "NA1_4386529401".
+	GetMatch(ctx context.Context, r region.Region, matchID string) (*Match, error)
+
+	// ----- Summoner API -----
+
+	// GetBySummonerPUUID returns a summoner by PUUID.
+	GetBySummonerPUUID(ctx context.Context, r region.Region, puuid string) (*apiclient.Summoner, error)
+
+	// GetByAccountID returns a summoner by account ID.
+	GetByAccountID(ctx context.Context, r region.Region, accountID string) (*apiclient.Summoner, error)
+
+	// GetBySummonerID returns a summoner by summoner ID.
+	GetBySummonerID(ctx context.Context, r region.Region, summonerID string) (*apiclient.Summoner, error)
+}
+
+// client is the internal implementation of Client.
+type client struct {
+	d *apiclient.Dispatcher
+}
+
+// New returns a Client configured for the given API key and underlying HTTP
+// client, with any given apiclient options applied. The returned Client is
+// threadsafe.
+func New(key string, httpClient external.Doer, limiter ratelimit.Limiter, opts ...apiclient.Option) Client {
+	return &client{d: apiclient.NewDispatcher(key, httpClient, limiter, opts...)}
+}
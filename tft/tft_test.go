@@ -0,0 +1,163 @@
+package tft
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/yuhanfang/riot/apiclient"
+	"github.com/yuhanfang/riot/constants/region"
+	"github.com/yuhanfang/riot/ratelimit"
+	"github.com/yuhanfang/riot/testing/doertest"
+)
+
+// routes returns a Doer that responds with the body of the first path prefix
+// that matches the request, or HTTP 404 if none matches.
+func routes(bodies map[string]string) doertest.DoerFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		status, body := http.StatusNotFound, `{"status": {"message": "Not found", "status_code": 404}}`
+		for prefix, b := range bodies {
+			if strings.HasPrefix(req.URL.Path, prefix) {
+				status, body = http.StatusOK, b
+			}
+		}
+		return doertest.Response(req, status, nil, body), nil
+	}
+}
+
+// recordingLimiter records the invocations acquired from the wrapped limiter.
+type recordingLimiter struct {
+	ratelimit.Limiter
+
+	mu          sync.Mutex
+	invocations []ratelimit.Invocation
+}
+
+func (l *recordingLimiter) Acquire(ctx context.Context, inv ratelimit.Invocation) (ratelimit.Done, ratelimit.Cancel, error) {
+	l.mu.Lock()
+	l.invocations = append(l.invocations, inv)
+	l.mu.Unlock()
+	return l.Limiter.Acquire(ctx, inv)
+}
+
+const match = `{
+  "metadata": {
+    "data_version": "5",
+    "match_id": "NA1_4386529401",
+    "participants": ["puuid-1"]
+  },
+  "info": {
+    "game_datetime": 1700000000000,
+    "game_length": 2012.5,
+    "queue_id": 1100,
+    "tft_game_type": "standard",
+    "tft_set_number": 10,
+    "participants": [{
+      "puuid": "puuid-1",
+      "placement": 2,
+      "level": 9,
+      "augments": ["TFT9_Augment_Example"],
+      "traits": [{"name": "Set10_Pentakill", "num_units": 5, "style": 2, "tier_current": 2, "tier_total": 4}],
+      "units": [{"character_id": "TFT10_Karthus", "itemNames": ["TFT_Item_JeweledGauntlet"], "rarity": 4, "tier": 2}]
+    }]
+  }
+}`
+
+func TestGetMatch(t *testing.T) {
+	c := New("key", routes(map[string]string{"/tft/match/v1/matches/": match}), ratelimit.NewLimiter())
+	m, err := c.GetMatch(context.Background(), region.NA1, "NA1_4386529401")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Metadata.MatchID != "NA1_4386529401" || m.Info.SetNumber != 10 || m.Info.GameDatetime.Time().Unix() != 1700000000 {
+		t.Errorf("got match %+v", m)
+	}
+	if len(m.Info.Participants) != 1 {
+		t.Fatalf("got %d participants, want 1", len(m.Info.Participants))
+	}
+	p := m.Info.Participants[0]
+	if p.Placement != 2 || !reflect.DeepEqual(p.Augments, []string{"TFT9_Augment_Example"}) {
+		t.Errorf("got participant %+v", p)
+	}
+	wantTrait := Trait{Name: "Set10_Pentakill", NumUnits: 5, Style: 2, TierCurrent: 2, TierTotal: 4}
+	if !reflect.DeepEqual(p.Traits, []Trait{wantTrait}) {
+		t.Errorf("got traits %+v, want %+v", p.Traits, wantTrait)
+	}
+	wantUnit := Unit{CharacterID: "TFT10_Karthus", ItemNames: []string{"TFT_Item_JeweledGauntlet"}, Rarity: 4, Tier: 2}
+	if !reflect.DeepEqual(p.Units, []Unit{wantUnit}) {
+		t.Errorf("got units %+v, want %+v", p.Units, wantUnit)
+	}
+}
+
+func TestHyperRollEntries(t *testing.T) {
+	c := New("key", routes(map[string]string{
+		"/tft/league/v1/entries/by-puuid/": `[{"queueType": "RANKED_TFT_TURBO", "ratedTier": "PURPLE", "ratedRating": 2345}]`,
+	}), ratelimit.NewLimiter())
+	entries, err := c.GetLeagueEntriesByPUUID(context.Background(), region.NA1, "puuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []LeagueEntry{{QueueType: QueueHyperRoll, RatedTier: "PURPLE", RatedRating: 2345}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("got entries %+v, want %+v", entries, want)
+	}
+}
+
+func TestTopLeagues(t *testing.T) {
+	var urls []string
+	c := New("key", doertest.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		urls = append(urls, req.URL.RequestURI())
+		return doertest.Response(req, http.StatusOK, nil, `{"tier": "CHALLENGER"}`), nil
+	}), ratelimit.NewLimiter())
+	ctx := context.Background()
+
+	if _, err := c.GetChallengerLeague(ctx, region.NA1, QueueHyperRoll); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetGrandmasterLeague(ctx, region.NA1, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetMasterLeague(ctx, region.NA1, QueueRanked); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"/tft/league/v1/challenger?queue=" + string(QueueHyperRoll),
+		"/tft/league/v1/grandmaster",
+		"/tft/league/v1/master?queue=" + string(QueueRanked),
+	}
+	if !reflect.DeepEqual(urls, want) {
+		t.Errorf("got URLs %v, want %v", urls, want)
+	}
+}
+
+// TestInvocationKeys checks that TFT methods do not share quota buckets with
+// the League of Legends methods of the same name.
+func TestInvocationKeys(t *testing.T) {
+	limiter := &recordingLimiter{Limiter: ratelimit.NewLimiter()}
+	doer := routes(map[string]string{"/": `{}`})
+	ctx := context.Background()
+
+	lol := apiclient.New("key", doer, limiter)
+	if _, err := lol.GetBySummonerPUUID(ctx, region.NA1, "puuid"); err != nil {
+		t.Fatal(err)
+	}
+	c := New("key", doer, limiter)
+	if _, err := c.GetBySummonerPUUID(ctx, region.NA1, "puuid"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetMatch(ctx, region.NA1, "NA1_1"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ratelimit.Invocation{
+		{ApplicationKey: "key", Region: "NA1", Method: "/lol/summoner/v4/summoners/by-puuid"},
+		{ApplicationKey: "key", Region: "NA1", Method: "/tft/summoner/v1/summoners/by-puuid"},
+		{ApplicationKey: "key", Region: "AMERICAS", Method: "/tft/match/v1/matches"},
+	}
+	if !reflect.DeepEqual(limiter.invocations, want) {
+		t.Errorf("got invocations %+v, want %+v", limiter.invocations, want)
+	}
+}